@@ -0,0 +1,163 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-zoox/command/agent/client"
+	"github.com/go-zoox/command/config"
+)
+
+// checkAgent rejects the settings whose results only exist on the agent,
+// as the agent does not return results, artifacts or changes.
+func checkAgent(cfg *Config) error {
+	unsupported := []string{}
+	if cfg.Language != "" {
+		unsupported = append(unsupported, "language")
+	}
+	if cfg.Build != nil {
+		unsupported = append(unsupported, "build")
+	}
+	if cfg.KeepContainer != "" {
+		unsupported = append(unsupported, "keep container")
+	}
+	if len(cfg.Artifacts) != 0 {
+		unsupported = append(unsupported, "artifacts")
+	}
+	if cfg.ReportChanges {
+		unsupported = append(unsupported, "report changes")
+	}
+
+	if len(unsupported) != 0 {
+		return fmt.Errorf("%s not supported by agent", strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// newAgentConfig creates the config sent to the agent from the command config.
+func newAgentConfig(cfg *Config) *config.Config {
+	return &config.Config{
+		// Context:                          cfg.Context,
+		Timeout:                          cfg.Timeout,
+		Engine:                           cfg.Engine,
+		Sandbox:                          cfg.Sandbox,
+		SandboxProfile:                   cfg.SandboxProfile,
+		Command:                          cfg.Command,
+		Script:                           cfg.Script,
+		Interpreter:                      cfg.Interpreter,
+		WorkDir:                          cfg.WorkDir,
+		Environment:                      cfg.Environment,
+		User:                             cfg.User,
+		Shell:                            cfg.Shell,
+		ReadOnly:                         cfg.ReadOnly,
+		TTY:                              cfg.TTY,
+		IsHistoryDisabled:                cfg.IsHistoryDisabled,
+		IsInheritEnvironmentEnabled:      cfg.IsInheritEnvironmentEnabled,
+		AllowedSystemEnvKeys:             cfg.AllowedSystemEnvKeys,
+		CgroupParent:                     cfg.CgroupParent,
+		Limits:                           cfg.Limits,
+		FSPolicy:                         cfg.FSPolicy,
+		Seccomp:                          cfg.Seccomp,
+		PidsLimit:                        cfg.PidsLimit,
+		IOLimits:                         cfg.IOLimits,
+		Image:                            cfg.Image,
+		Memory:                           cfg.Memory,
+		CPU:                              cfg.CPU,
+		MemorySwap:                       cfg.MemorySwap,
+		MemoryReservation:                cfg.MemoryReservation,
+		CPUSet:                           cfg.CPUSet,
+		ShmSize:                          cfg.ShmSize,
+		Ulimits:                          cfg.Ulimits,
+		BlkioWeight:                      cfg.BlkioWeight,
+		StorageSize:                      cfg.StorageSize,
+		Platform:                         cfg.Platform,
+		Network:                          cfg.Network,
+		DisableNetwork:                   cfg.DisableNetwork,
+		Networks:                         cfg.Networks,
+		NetworkAliases:                   cfg.NetworkAliases,
+		Hostname:                         cfg.Hostname,
+		Ports:                            cfg.Ports,
+		DNS:                              cfg.DNS,
+		ExtraHosts:                       cfg.ExtraHosts,
+		Services:                         cfg.Services,
+		EgressAllow:                      cfg.EgressAllow,
+		Privileged:                       cfg.Privileged,
+		DockerHost:                       cfg.DockerHost,
+		ImageRegistry:                    cfg.ImageRegistry,
+		ImageRegistryUsername:            cfg.ImageRegistryUsername,
+		ImageRegistryPassword:            cfg.ImageRegistryPassword,
+		PullPolicy:                       cfg.PullPolicy,
+		RegistryAuths:                    cfg.RegistryAuths,
+		DockerRuntime:                    cfg.DockerRuntime,
+		Mounts:                           cfg.Mounts,
+		DisableWorkDirMount:              cfg.DisableWorkDirMount,
+		Server:                           cfg.Server,
+		ClientID:                         cfg.ClientID,
+		ClientSecret:                     cfg.ClientSecret,
+		SSHHost:                          cfg.SSHHost,
+		SSHPort:                          cfg.SSHPort,
+		SSHUser:                          cfg.SSHUser,
+		SSHPass:                          cfg.SSHPass,
+		SSHPrivateKey:                    cfg.SSHPrivateKey,
+		SSHPrivateKeySecret:              cfg.SSHPrivateKeySecret,
+		SSHIsIgnoreStrictHostKeyChecking: cfg.SSHIsIgnoreStrictHostKeyChecking,
+		SSHKnowHostsFilePath:             cfg.SSHKnowHostsFilePath,
+		K8sKubeconfig:                    cfg.K8sKubeconfig,
+		K8sNamespace:                     cfg.K8sNamespace,
+		K8sImage:                         cfg.K8sImage,
+		K8sPodTimeoutSeconds:             cfg.K8sPodTimeoutSeconds,
+		PodmanHost:                       cfg.PodmanHost,
+		WSLDistro:                        cfg.WSLDistro,
+		ID:                               cfg.ID,
+		DataDirOuter:                     cfg.DataDirOuter,
+		DataDirInner:                     cfg.DataDirInner,
+	}
+}
+
+// agentCommand adapts the agent client to the Command interface.
+type agentCommand struct {
+	client.Client
+}
+
+// Exec is not supported by agent.
+func (a *agentCommand) Exec(cfg *Config) (Command, error) {
+	return nil, errors.New("exec is not supported by agent")
+}
+
+// Result is not supported by agent.
+func (a *agentCommand) Result() *Result {
+	return nil
+}
+
+// Stats is not supported by agent.
+func (a *agentCommand) Stats() (*Stats, error) {
+	return nil, errors.New("stats are not supported by agent")
+}
+
+// Ports is not supported by agent.
+func (a *agentCommand) Ports() ([]Port, error) {
+	return nil, errors.New("ports are not supported by agent")
+}
+
+// Commit is not supported by agent.
+func (a *agentCommand) Commit(ref string) (string, error) {
+	return "", errors.New("commit is not supported by agent")
+}
+
+// Cleanup is not supported by agent.
+func (a *agentCommand) Cleanup() error {
+	return errors.New("cleanup is not supported by agent")
+}
+
+// CopyTo is not supported by agent.
+func (a *agentCommand) CopyTo(ctx context.Context, src, dst string) error {
+	return errors.New("copy is not supported by agent")
+}
+
+// CopyFrom is not supported by agent.
+func (a *agentCommand) CopyFrom(ctx context.Context, src, dst string) error {
+	return errors.New("copy is not supported by agent")
+}
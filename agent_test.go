@@ -0,0 +1,38 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew_AgentRejectsLocalResults(t *testing.T) {
+	_, err := New(&Config{
+		Command:       "echo test",
+		Agent:         "ws://127.0.0.1:1",
+		WorkDir:       t.TempDir(),
+		Artifacts:     []string{"out"},
+		ReportChanges: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "artifacts, report changes not supported by agent") {
+		t.Errorf("expected artifacts and changes rejected, got %v", err)
+	}
+}
+
+func TestNewAgentConfig(t *testing.T) {
+	cfg := newAgentConfig(&Config{
+		Command:     "echo test",
+		Engine:      "docker",
+		Seccomp:     "strict",
+		EgressAllow: []string{"example.com"},
+		Mounts:      []Mount{{Type: "tmpfs", Target: "/cache"}},
+		MemorySwap:  512,
+		CPUSet:      "0-1",
+		Ulimits:     []Ulimit{{Name: "nofile", Soft: 1024, Hard: 1024}},
+		StorageSize: 1024,
+	})
+
+	if cfg.Seccomp != "strict" || len(cfg.EgressAllow) != 1 || len(cfg.Mounts) != 1 ||
+		cfg.MemorySwap != 512 || cfg.CPUSet != "0-1" || len(cfg.Ulimits) != 1 || cfg.StorageSize != 1024 {
+		t.Errorf("expected the settings forwarded to the agent, got %+v", cfg)
+	}
+}
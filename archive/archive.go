@@ -0,0 +1,280 @@
+// Package archive streams files and directories as tar archives, which is
+// how files are copied in and out of the execution environments.
+package archive
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Tar writes the file or directory src to w as a tar stream.
+// Entries are named relative to the parent of src, so the archive
+// contains a single top-level entry named after src.
+func Tar(w io.Writer, src string) error {
+	tw := tar.NewWriter(w)
+
+	src = filepath.Clean(src)
+	base := filepath.Dir(src)
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(base, file)
+		if err != nil {
+			return err
+		}
+
+		return addFile(tw, file, filepath.ToSlash(name), info)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+func addFile(tw *tar.Writer, file, name string, info os.FileInfo) error {
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(file)
+		if err != nil {
+			return err
+		}
+		link = target
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Checksum returns the size and the hex-encoded sha256 checksum of the content
+// of the first entry of the tar stream r.
+func Checksum(r io.Reader) (int64, string, error) {
+	tr := tar.NewReader(r)
+	if _, err := tr.Next(); err != nil {
+		return 0, "", err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, tr)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Untar extracts the tar stream r into the directory dst, creating it if needed.
+// Entries escaping dst, symlinks pointing outside of it or replacing a
+// directory and entries below a symlink are rejected.
+func Untar(r io.Reader, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := Join(dst, header.Name)
+		if err != nil {
+			return err
+		}
+		if err := checkParents(dst, target); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			// replace a symlink instead of writing through it
+			if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(target); err != nil {
+					return err
+				}
+			}
+
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := CheckLink(header.Name, header.Linkname); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+
+			// a directory is not replaced, the targets of other symlinks may climb it
+			if info, err := os.Lstat(target); err == nil && info.IsDir() {
+				return fmt.Errorf("archive: symlink %q would replace a directory", header.Name)
+			}
+			os.Remove(target)
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			// devices, fifos and hard links are not copied
+		}
+	}
+}
+
+// Join joins the entry name to dir, rejecting names escaping dir.
+func Join(dir, name string) (string, error) {
+	clean := path.Clean("/" + filepath.ToSlash(name))
+	if clean == "/" || strings.Contains(name, "\x00") {
+		return dir, nil
+	}
+
+	target := filepath.Join(dir, filepath.FromSlash(clean))
+	if rel, err := filepath.Rel(dir, target); err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("archive: entry %q escapes the destination", name)
+	}
+
+	return target, nil
+}
+
+// CheckLink rejects the target of the symlink entry name if it is absolute or
+// resolves outside of the destination the entry is extracted into. ".." is
+// only accepted at the start of the target, where it climbs the directories
+// of the entry itself: after another component it could climb out of a
+// symlink extracted before, e.g. a/c -> b/.. with a/b -> ...
+func CheckLink(name, linkname string) error {
+	if linkname == "" || path.IsAbs(linkname) || filepath.IsAbs(linkname) {
+		return fmt.Errorf("archive: symlink %q has an absolute or empty target %q", name, linkname)
+	}
+
+	isDescending := false
+	for _, part := range strings.Split(filepath.ToSlash(linkname), "/") {
+		switch part {
+		case "", ".":
+		case "..":
+			if isDescending {
+				return fmt.Errorf("archive: symlink %q has \"..\" after another component in its target %q", name, linkname)
+			}
+		default:
+			isDescending = true
+		}
+	}
+
+	dir := path.Dir(strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/"))
+	resolved := path.Join(dir, filepath.ToSlash(linkname))
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return fmt.Errorf("archive: symlink %q points outside the destination: %q", name, linkname)
+	}
+
+	return nil
+}
+
+// checkParents rejects the target if a directory between dst and it is a
+// symlink, which the entry would be written through.
+func checkParents(dst, target string) error {
+	rel, err := filepath.Rel(dst, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return err
+	}
+
+	dir := dst
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("archive: entry %q is below the symlink %q", target, dir)
+		}
+	}
+
+	return nil
+}
+
+// Rebase returns a tar stream with every entry of r moved under prefix.
+func Rebase(r io.Reader, prefix string) io.ReadCloser {
+	prefix = strings.Trim(path.Clean("/"+prefix), "/")
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(rebase(pw, r, prefix))
+	}()
+
+	return pr
+}
+
+func rebase(w io.Writer, r io.Reader, prefix string) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		if prefix != "" {
+			header.Name = prefix + "/" + strings.TrimPrefix(header.Name, "/")
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTarUntar_Directory(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "project")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Tar(buf, src); err != nil {
+		t.Fatalf("Tar: %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := Untar(buf, dst); err != nil {
+		t.Fatalf("Untar: %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dst, "project", "sub", "a.txt"))
+	if err != nil {
+		t.Fatalf("read extracted file: %v", err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("extracted content = %q, want hello", b)
+	}
+}
+
+func TestUntar_RejectsEscapingEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+
+	dst := t.TempDir()
+	if err := Untar(buf, dst); err != nil {
+		t.Fatalf("Untar: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dst), "evil")); err == nil {
+		t.Fatal("entry escaped the destination directory")
+	}
+}
+
+func TestUntar_RejectsWritesThroughSymlinks(t *testing.T) {
+	outside := t.TempDir()
+
+	for _, linkname := range []string{outside, "../" + filepath.Base(outside), "sub/../../x"} {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		tw.WriteHeader(&tar.Header{Name: "link", Linkname: linkname, Typeflag: tar.TypeSymlink})
+		tw.WriteHeader(&tar.Header{Name: "link/evil", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+		tw.Write([]byte("x"))
+		tw.Close()
+
+		if err := Untar(buf, t.TempDir()); err == nil {
+			t.Errorf("%s: expected the symlink to be rejected", linkname)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "evil")); err == nil {
+			t.Fatalf("%s: entry was written through the symlink outside the destination", linkname)
+		}
+	}
+
+	// a symlink inside the destination is extracted, but not written through
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "sub/", Mode: 0755, Typeflag: tar.TypeDir})
+	tw.WriteHeader(&tar.Header{Name: "link", Linkname: "sub", Typeflag: tar.TypeSymlink})
+	tw.WriteHeader(&tar.Header{Name: "link/evil", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+
+	dst := t.TempDir()
+	if err := Untar(buf, dst); err == nil {
+		t.Error("expected the entry below the symlink to be rejected")
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "link")); err != nil || target != "sub" {
+		t.Errorf("expected the symlink link -> sub, got %q %v", target, err)
+	}
+}
+
+func TestUntar_RejectsChainedSymlinks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "a/", Mode: 0755, Typeflag: tar.TypeDir})
+	tw.WriteHeader(&tar.Header{Name: "a/b", Linkname: "..", Typeflag: tar.TypeSymlink})
+	tw.WriteHeader(&tar.Header{Name: "a/c", Linkname: "b/..", Typeflag: tar.TypeSymlink})
+	tw.Close()
+
+	dst := t.TempDir()
+	if err := Untar(buf, dst); err == nil {
+		t.Error("expected the chained symlink to be rejected")
+	}
+	if _, err := os.Lstat(filepath.Join(dst, "a", "c")); err == nil {
+		t.Error("expected no symlink a/c pointing outside the destination")
+	}
+
+	// an empty directory climbed by a symlink cannot be replaced by another one
+	buf = &bytes.Buffer{}
+	tw = tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "a/b/", Mode: 0755, Typeflag: tar.TypeDir})
+	tw.WriteHeader(&tar.Header{Name: "a/b", Linkname: "..", Typeflag: tar.TypeSymlink})
+	tw.Close()
+
+	if err := Untar(buf, t.TempDir()); err == nil {
+		t.Error("expected the directory not to be replaced by a symlink")
+	}
+}
+
+func TestCheckLink(t *testing.T) {
+	for link, ok := range map[[2]string]bool{
+		{"a/link", "../b"}:       true,
+		{"a/link", "b/c"}:        true,
+		{"link", "../b"}:         false,
+		{"a/link", "../../b"}:    false,
+		{"a/link", "/etc"}:       false,
+		{"../a/link", "../b"}:    true,
+		{"/a/b/link", "../.."}:   true,
+		{"a/b/link", "../../.."}: false,
+		{"a/c", "b/.."}:          false,
+		{"a/c", "./../b/./c"}:    true,
+	} {
+		if err := CheckLink(link[0], link[1]); (err == nil) != ok {
+			t.Errorf("CheckLink(%q, %q) = %v, expected ok: %t", link[0], link[1], err, ok)
+		}
+	}
+}
+
+func TestRebase(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "a.txt", Mode: 0644, Size: 2, Typeflag: tar.TypeReg})
+	tw.Write([]byte("ok"))
+	tw.Close()
+
+	tr := tar.NewReader(Rebase(buf, "/workspace/in"))
+	header, err := tr.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if header.Name != "workspace/in/a.txt" {
+		t.Errorf("Name = %q, want workspace/in/a.txt", header.Name)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(file, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Tar(buf, file); err != nil {
+		t.Fatalf("Tar: %v", err)
+	}
+
+	size, sum, err := Checksum(buf)
+	if err != nil {
+		t.Fatalf("Checksum: %v", err)
+	}
+	if size != 5 || sum != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("Checksum = %d %s", size, sum)
+	}
+}
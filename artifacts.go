@@ -0,0 +1,195 @@
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-zoox/command/archive"
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/dind"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/host"
+	"github.com/go-zoox/command/engine/podman"
+	"github.com/go-zoox/command/engine/ssh"
+	"github.com/go-zoox/command/result"
+)
+
+// ErrArtifactTooLarge is returned when the artifacts exceed ArtifactsMaxSize or ArtifactsMaxFileSize.
+var ErrArtifactTooLarge = errors.New("artifact exceeds size limit")
+
+// isArtifactsSupported reports whether the environment of the engine is still
+// reachable after the command exits.
+func isArtifactsSupported(name string) bool {
+	switch name {
+	case host.Name, docker.Name, podman.Name, dind.Name, ssh.Name:
+		return true
+	default:
+		return false
+	}
+}
+
+// collectArtifacts copies the files matching Artifacts out of the working directory
+// into ArtifactsDir and ArtifactsWriter, returning their manifest.
+func (c *command) collectArtifacts(ctx context.Context) (artifacts []result.Artifact, err error) {
+	copier, ok := c.engine.(engine.Copier)
+	if !ok {
+		return nil, fmt.Errorf("artifacts are not supported by engine: %s", c.cfg.Engine)
+	}
+
+	workdir := c.cfg.WorkDir
+	if workdir == "" {
+		if c.cfg.Engine != host.Name {
+			return nil, fmt.Errorf("artifacts require WorkDir for engine: %s", c.cfg.Engine)
+		}
+
+		if workdir, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+
+	reader, err := copier.CopyFrom(ctx, workdir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifacts: %w", err)
+	}
+	defer reader.Close()
+
+	var tw *tar.Writer
+	if c.cfg.ArtifactsWriter != nil {
+		gw := gzip.NewWriter(c.cfg.ArtifactsWriter)
+		tw = tar.NewWriter(gw)
+		defer func() {
+			if errx := tw.Close(); err == nil {
+				err = errx
+			}
+			if errx := gw.Close(); err == nil {
+				err = errx
+			}
+		}()
+	}
+
+	var total int64
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return artifacts, nil
+		}
+		if err != nil {
+			return artifacts, fmt.Errorf("failed to read artifacts: %w", err)
+		}
+
+		// entries are rooted at the base name of the working directory
+		_, name, _ := strings.Cut(strings.TrimSuffix(header.Name, "/"), "/")
+		if header.Typeflag != tar.TypeReg || !matchArtifact(c.cfg.Artifacts, name) {
+			continue
+		}
+
+		if c.cfg.ArtifactsMaxFileSize != 0 && header.Size > c.cfg.ArtifactsMaxFileSize {
+			return artifacts, fmt.Errorf("%w: %s (%d bytes)", ErrArtifactTooLarge, name, header.Size)
+		}
+		total += header.Size
+		if c.cfg.ArtifactsMaxSize != 0 && total > c.cfg.ArtifactsMaxSize {
+			return artifacts, fmt.Errorf("%w: total size exceeds %d bytes", ErrArtifactTooLarge, c.cfg.ArtifactsMaxSize)
+		}
+
+		artifact, err := c.writeArtifact(tr, tw, header, name)
+		if err != nil {
+			return artifacts, fmt.Errorf("failed to write artifact %s: %w", name, err)
+		}
+
+		artifacts = append(artifacts, *artifact)
+	}
+}
+
+func (c *command) writeArtifact(r io.Reader, tw *tar.Writer, header *tar.Header, name string) (*result.Artifact, error) {
+	hash := sha256.New()
+	writers := []io.Writer{hash}
+
+	if c.cfg.ArtifactsDir != "" {
+		target, err := archive.Join(c.cfg.ArtifactsDir, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		writers = append(writers, f)
+	}
+
+	if tw != nil {
+		entry := *header
+		entry.Name = name
+		if err := tw.WriteHeader(&entry); err != nil {
+			return nil, err
+		}
+
+		writers = append(writers, tw)
+	}
+
+	size, err := io.Copy(io.MultiWriter(writers...), r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Artifact{
+		Path:   name,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// matchArtifact reports whether name or one of its parent directories matches one of the patterns.
+func matchArtifact(patterns []string, name string) bool {
+	segments := strings.Split(name, "/")
+	for _, pattern := range patterns {
+		pattern = strings.Trim(path.Clean(filepath.ToSlash(pattern)), "/")
+		for i := 1; i <= len(segments); i++ {
+			if matchSegments(strings.Split(pattern, "/"), segments[:i]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+
+	return matchSegments(pattern[1:], segments[1:])
+}
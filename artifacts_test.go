@@ -0,0 +1,123 @@
+package command
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArtifacts_Host(t *testing.T) {
+	workdir := t.TempDir()
+	dir := t.TempDir()
+	stream := &bytes.Buffer{}
+
+	cmd, err := New(&Config{
+		Command:         "mkdir -p dist/sub && echo app > dist/app && echo lib > dist/sub/lib.so && echo log > build.log && echo tmp > tmp.txt",
+		WorkDir:         workdir,
+		Artifacts:       []string{"dist", "*.log"},
+		ArtifactsDir:    dir,
+		ArtifactsWriter: stream,
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	r := cmd.Result()
+	if r == nil {
+		t.Fatal("expected result after Run")
+	}
+
+	manifest := map[string]Artifact{}
+	for _, a := range r.Artifacts {
+		manifest[a.Path] = a
+	}
+	if len(manifest) != 3 {
+		t.Fatalf("expected 3 artifacts, got %+v", r.Artifacts)
+	}
+
+	sum := sha256.Sum256([]byte("app\n"))
+	if a := manifest["dist/app"]; a.Size != 4 || a.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected manifest entry for dist/app: %+v", a)
+	}
+
+	if b, err := os.ReadFile(filepath.Join(dir, "dist", "sub", "lib.so")); err != nil || string(b) != "lib\n" {
+		t.Errorf("expected dist/sub/lib.so in artifacts dir, got %q (%v)", b, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tmp.txt")); err == nil {
+		t.Error("expected tmp.txt not to be collected")
+	}
+
+	gr, err := gzip.NewReader(stream)
+	if err != nil {
+		t.Fatalf("invalid tar.gz stream: %v", err)
+	}
+	var names []string
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	if len(names) != 3 {
+		t.Errorf("expected 3 entries in tar.gz stream, got %v", names)
+	}
+}
+
+func TestArtifacts_SizeLimit(t *testing.T) {
+	cmd, err := New(&Config{
+		Command:              "echo 0123456789 > big.txt",
+		WorkDir:              t.TempDir(),
+		Artifacts:            []string{"big.txt"},
+		ArtifactsMaxFileSize: 4,
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	if err := cmd.Run(); !errors.Is(err, ErrArtifactTooLarge) {
+		t.Fatalf("expected ErrArtifactTooLarge, got %v", err)
+	}
+}
+
+func TestArtifacts_UnsupportedEngine(t *testing.T) {
+	_, err := New(&Config{
+		Command:   "true",
+		Engine:    "caas",
+		Artifacts: []string{"*"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "artifacts are not supported") {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
+
+func TestMatchArtifact(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.log", "build.log", true},
+		{"*.log", "logs/build.log", false},
+		{"**/*.log", "logs/build.log", true},
+		{"**/*.log", "build.log", true},
+		{"dist", "dist/sub/app", true},
+		{"./dist/", "dist/app", true},
+		{"dist/*.so", "dist/app", false},
+	}
+	for _, c := range cases {
+		if got := matchArtifact([]string{c.pattern}, c.name); got != c.want {
+			t.Errorf("matchArtifact(%q, %q) = %v, want %v", c.pattern, c.name, got, c.want)
+		}
+	}
+}
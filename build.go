@@ -0,0 +1,65 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/podman"
+)
+
+// Build describes the image a command runs in, built before it starts.
+type Build = config.Build
+
+// checkBuild validates the image build for the engine.
+func checkBuild(cfg *Config) error {
+	switch cfg.Engine {
+	case docker.Name, podman.Name:
+	default:
+		return fmt.Errorf("image build is only supported by docker and podman engines, but got: %s", cfg.Engine)
+	}
+
+	if cfg.Image != "" {
+		return fmt.Errorf("build and image are mutually exclusive")
+	}
+
+	build := cfg.Build
+	if build.Dockerfile != "" && build.DockerfilePath != "" {
+		return fmt.Errorf("build dockerfile and dockerfile path are mutually exclusive")
+	}
+	if build.Dockerfile == "" && build.Context == "" {
+		return fmt.Errorf("build requires a dockerfile or a context")
+	}
+
+	if build.Context != "" {
+		info, err := os.Stat(build.Context)
+		if err != nil {
+			return fmt.Errorf("invalid build context: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("build context must be a directory: %s", build.Context)
+		}
+	}
+
+	if build.Dockerfile == "" {
+		path := build.DockerfilePath
+		if path == "" {
+			path = "Dockerfile"
+		}
+		if !filepath.IsLocal(path) {
+			return fmt.Errorf("build dockerfile path must be inside the context: %s", path)
+		}
+
+		info, err := os.Lstat(filepath.Join(build.Context, path))
+		if err != nil {
+			return fmt.Errorf("invalid build dockerfile path: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("build dockerfile path must be a file: %s", path)
+		}
+	}
+
+	return nil
+}
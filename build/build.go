@@ -0,0 +1,157 @@
+// Package build prepares the image builds of commands: a reproducible build
+// context holding the Dockerfile, and the content-addressed tag of the image,
+// so an unchanged build reuses the cached image.
+package build
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-zoox/command/config"
+)
+
+// Repository is the repository of the built images.
+const Repository = "go-zoox-command-build"
+
+// InlineDockerfile is the name of the inline Dockerfile in the build context.
+const InlineDockerfile = ".go-zoox-command.Dockerfile"
+
+// Context is a prepared build.
+type Context struct {
+	// Tar is the build context as a tar stream
+	Tar []byte
+	// Dockerfile is the path of the Dockerfile in the build context
+	Dockerfile string
+	// Tag is the content-addressed reference of the image
+	Tag string
+}
+
+// Prepare archives the build context with the Dockerfile and tags the image
+// with the checksum of the context, the build args, the target and the platform.
+func Prepare(b *config.Build, platform string) (*Context, error) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+
+	if b.Context != "" {
+		if err := addDir(tw, b.Context); err != nil {
+			return nil, fmt.Errorf("failed to archive build context %s: %w", b.Context, err)
+		}
+	}
+
+	dockerfile := b.DockerfilePath
+	if b.Dockerfile != "" {
+		dockerfile = InlineDockerfile
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    dockerfile,
+			Mode:    0644,
+			Size:    int64(len(b.Dockerfile)),
+			ModTime: time.Unix(0, 0),
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(tw, b.Dockerfile); err != nil {
+			return nil, err
+		}
+	} else if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	hash.Write(buf.Bytes())
+	fmt.Fprintf(hash, "\x00dockerfile=%s\x00target=%s\x00platform=%s", dockerfile, b.Target, platform)
+
+	keys := make([]string, 0, len(b.Args))
+	for key := range b.Args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(hash, "\x00arg=%s=%s", key, b.Args[key])
+	}
+
+	return &Context{
+		Tar:        buf.Bytes(),
+		Dockerfile: dockerfile,
+		Tag:        fmt.Sprintf("%s:%s", Repository, hex.EncodeToString(hash.Sum(nil))),
+	}, nil
+}
+
+// Args returns the build args as the build API takes them.
+func Args(args map[string]string) map[string]*string {
+	result := map[string]*string{}
+	for key, value := range args {
+		value := value
+		result[key] = &value
+	}
+
+	return result
+}
+
+// addDir adds the files below dir to the tar stream, in a stable order and without
+// the metadata which does not change the build (modification times, owners).
+func addDir(tw *tar.Writer, dir string) error {
+	return filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if file == dir {
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		header.ModTime = time.Unix(0, 0)
+		header.AccessTime = time.Time{}
+		header.ChangeTime = time.Time{}
+		header.Uid, header.Gid = 0, 0
+		header.Uname, header.Gname = "", ""
+		header.Format = tar.FormatPAX
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
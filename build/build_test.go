@@ -0,0 +1,78 @@
+package build
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/command/config"
+)
+
+func TestPrepare_Inline(t *testing.T) {
+	prepared, err := Prepare(&config.Build{Dockerfile: "FROM alpine\nRUN apk add curl\n"}, "")
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+
+	if prepared.Dockerfile != InlineDockerfile || !strings.HasPrefix(prepared.Tag, Repository+":") {
+		t.Errorf("unexpected build: %s %s", prepared.Dockerfile, prepared.Tag)
+	}
+
+	tr := tar.NewReader(bytes.NewReader(prepared.Tar))
+	header, err := tr.Next()
+	if err != nil || header.Name != InlineDockerfile {
+		t.Fatalf("expected the inline Dockerfile, got %v, %v", header, err)
+	}
+	if content, _ := io.ReadAll(tr); string(content) != "FROM alpine\nRUN apk add curl\n" {
+		t.Errorf("unexpected Dockerfile: %q", content)
+	}
+}
+
+func TestPrepare_Tag(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(file, []byte("FROM alpine\nCOPY . /app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &config.Build{Context: dir, Args: map[string]string{"A": "1", "B": "2"}}
+	first, err := Prepare(b, "linux/amd64")
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	if first.Dockerfile != "Dockerfile" {
+		t.Errorf("expected the default Dockerfile, got %s", first.Dockerfile)
+	}
+
+	// touching a file does not change the content
+	if err := os.Chtimes(file, time.Now(), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := Prepare(b, "linux/amd64"); again.Tag != first.Tag {
+		t.Errorf("expected the same tag, got %s and %s", first.Tag, again.Tag)
+	}
+
+	for name, changed := range map[string]*config.Build{
+		"args":   {Context: dir, Args: map[string]string{"A": "1", "B": "3"}},
+		"target": {Context: dir, Args: b.Args, Target: "test"},
+	} {
+		if prepared, _ := Prepare(changed, "linux/amd64"); prepared.Tag == first.Tag {
+			t.Errorf("expected another tag for changed %s", name)
+		}
+	}
+	if prepared, _ := Prepare(b, "linux/arm64"); prepared.Tag == first.Tag {
+		t.Error("expected another tag for another platform")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if prepared, _ := Prepare(b, "linux/amd64"); prepared.Tag == first.Tag {
+		t.Error("expected another tag for changed context")
+	}
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckBuild(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(file, []byte("FROM alpine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkBuild(&Config{Engine: "docker", Build: &Build{Context: dir}}); err != nil {
+		t.Errorf("checkBuild: %v", err)
+	}
+	if err := checkBuild(&Config{Engine: "podman", Build: &Build{Dockerfile: "FROM alpine\n"}}); err != nil {
+		t.Errorf("checkBuild: %v", err)
+	}
+
+	for _, tc := range []struct {
+		cfg *Config
+		err string
+	}{
+		{&Config{Engine: "k8s", Build: &Build{Dockerfile: "FROM alpine"}}, "only supported by docker and podman"},
+		{&Config{Engine: "docker", Image: "alpine", Build: &Build{Dockerfile: "FROM alpine"}}, "mutually exclusive"},
+		{&Config{Engine: "docker", Build: &Build{Dockerfile: "FROM alpine", DockerfilePath: "Dockerfile", Context: dir}}, "mutually exclusive"},
+		{&Config{Engine: "docker", Build: &Build{Args: map[string]string{"A": "1"}}}, "requires a dockerfile or a context"},
+		{&Config{Engine: "docker", Build: &Build{Context: filepath.Join(dir, "missing")}}, "invalid build context"},
+		{&Config{Engine: "docker", Build: &Build{Context: file}}, "must be a directory"},
+		{&Config{Engine: "docker", Build: &Build{Context: dir, DockerfilePath: "missing"}}, "invalid build dockerfile path"},
+		{&Config{Engine: "docker", Build: &Build{Context: dir, DockerfilePath: "../Dockerfile"}}, "must be inside the context"},
+		{&Config{Engine: "docker", Build: &Build{Context: dir, DockerfilePath: "/etc/passwd"}}, "must be inside the context"},
+		{&Config{Engine: "docker", Build: &Build{Context: dir, DockerfilePath: "."}}, "must be a file"},
+	} {
+		if err := checkBuild(tc.cfg); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("expected error containing %q, got %v", tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/host"
+	"github.com/go-zoox/command/engine/namespace"
+	"github.com/go-zoox/command/engine/podman"
+	"github.com/go-zoox/command/result"
+)
+
+// Change is a filesystem change made by the command.
+type Change = result.Change
+
+// isChangesSupported reports whether the engine can report the filesystem changes.
+func isChangesSupported(name string) bool {
+	switch name {
+	case host.Name, namespace.Name, docker.Name, podman.Name:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkChanges validates the change report for the engine.
+func checkChanges(cfg *Config) error {
+	if !isChangesSupported(cfg.Engine) {
+		return fmt.Errorf("change report is not supported by engine: %s", cfg.Engine)
+	}
+
+	// without WorkDir, the namespace engine runs in a tmpfs which is gone after exit
+	if cfg.Engine == namespace.Name && cfg.WorkDir == "" {
+		return fmt.Errorf("change report requires WorkDir for engine: %s", cfg.Engine)
+	}
+
+	return nil
+}
+
+// resolveSnapshotDir returns the local directory whose changes are found by comparing
+// snapshots: the working directory of the host and namespace engines, and the
+// bind-mounted WorkDir of the docker engine. It is empty if there is none.
+func (c *command) resolveSnapshotDir() (string, error) {
+	switch c.cfg.Engine {
+	case host.Name:
+		if c.cfg.WorkDir == "" {
+			return os.Getwd()
+		}
+	case namespace.Name:
+	case docker.Name:
+		if _, err := os.Stat(c.cfg.WorkDir); c.cfg.WorkDir == "" || c.cfg.DisableWorkDirMount || err != nil {
+			return "", nil
+		}
+	default:
+		return "", nil
+	}
+
+	return c.cfg.WorkDir, nil
+}
+
+// fileState is the state of a path in a snapshot.
+type fileState struct {
+	mode    fs.FileMode
+	size    int64
+	modTime time.Time
+}
+
+// snapshot is the state of the paths below a directory.
+type snapshot map[string]fileState
+
+// takeSnapshot records the metadata of the paths below dir. Contents are not
+// hashed, a file is modified if its size, mode or modification time changed.
+func takeSnapshot(dir string) (snapshot, error) {
+	s := snapshot{}
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if file == dir {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		s[file] = fileState{mode: info.Mode(), size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot %s: %w", dir, err)
+	}
+
+	return s, nil
+}
+
+// compare returns the changes from the snapshot before to after, with the
+// checksums of the added and modified regular files.
+func compare(before, after snapshot) ([]result.Change, error) {
+	changes := []result.Change{}
+	for file, state := range after {
+		old, ok := before[file]
+
+		kind := result.ChangeAdded
+		if ok {
+			// directories change with their entries, which are reported themselves
+			if state.mode.IsDir() && old.mode.IsDir() {
+				continue
+			}
+			if state.mode == old.mode && state.size == old.size && state.modTime.Equal(old.modTime) {
+				continue
+			}
+			kind = result.ChangeModified
+		}
+
+		change := result.Change{Path: file, Kind: kind, IsDir: state.mode.IsDir()}
+		if state.mode.IsRegular() {
+			size, sum, err := checksum(file)
+			if err != nil {
+				return nil, err
+			}
+			change.Size, change.SHA256 = size, sum
+		} else if !state.mode.IsDir() {
+			change.Size = state.size
+		}
+
+		changes = append(changes, change)
+	}
+
+	for file, state := range before {
+		if _, ok := after[file]; !ok {
+			change := result.Change{Path: file, Kind: result.ChangeDeleted, IsDir: state.mode.IsDir()}
+			if !change.IsDir {
+				change.Size = state.size
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+func checksum(file string) (int64, string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// snapshotChanges takes the snapshot the changes are compared against, before the command starts.
+func (c *command) snapshotChanges() (err error) {
+	if c.snapshotDir, err = c.resolveSnapshotDir(); err != nil || c.snapshotDir == "" {
+		return err
+	}
+
+	c.snapshot, err = takeSnapshot(c.snapshotDir)
+	return err
+}
+
+// reportChanges returns the changes of the snapshot directory and of the
+// environment of the engine, which is only reachable if the command exited.
+func (c *command) reportChanges(ctx context.Context, exited bool) ([]result.Change, error) {
+	changes := []result.Change{}
+
+	if c.snapshot != nil {
+		after, err := takeSnapshot(c.snapshotDir)
+		if err != nil {
+			return nil, err
+		}
+
+		if changes, err = compare(c.snapshot, after); err != nil {
+			return nil, fmt.Errorf("failed to report changes: %w", err)
+		}
+	}
+
+	if differ, ok := c.engine.(engine.Differ); ok && exited {
+		diff, err := differ.Diff(ctx)
+		if err != nil {
+			return changes, fmt.Errorf("failed to report changes: %w", err)
+		}
+		changes = append(changes, diff...)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReportChanges_Host(t *testing.T) {
+	workdir := t.TempDir()
+	for name, content := range map[string]string{"modified.txt": "old\n", "deleted.txt": "gone\n", "kept.txt": "kept\n"} {
+		if err := os.WriteFile(filepath.Join(workdir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd, err := New(&Config{
+		Command:       "echo new >> modified.txt && rm deleted.txt && mkdir dir && echo added > dir/added.txt",
+		WorkDir:       workdir,
+		ReportChanges: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	changes := map[string]Change{}
+	for _, change := range cmd.Result().Changes {
+		changes[strings.TrimPrefix(change.Path, workdir+"/")] = change
+	}
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %+v", cmd.Result().Changes)
+	}
+
+	sum := sha256.Sum256([]byte("old\nnew\n"))
+	if c := changes["modified.txt"]; c.Kind != "modified" || c.Size != 8 || c.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected change of modified.txt: %+v", c)
+	}
+	if c := changes["deleted.txt"]; c.Kind != "deleted" || c.Size != 5 {
+		t.Errorf("unexpected change of deleted.txt: %+v", c)
+	}
+	if c := changes["dir"]; c.Kind != "added" || !c.IsDir {
+		t.Errorf("unexpected change of dir: %+v", c)
+	}
+	if c := changes["dir/added.txt"]; c.Kind != "added" || c.Size != 6 || c.SHA256 == "" {
+		t.Errorf("unexpected change of dir/added.txt: %+v", c)
+	}
+}
+
+func TestReportChanges_Unsupported(t *testing.T) {
+	if _, err := New(&Config{Command: "echo", Engine: "ssh", ReportChanges: true}); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected unsupported engine error, got %v", err)
+	}
+
+	if _, err := New(&Config{Command: "echo", Engine: "namespace", ReportChanges: true}); err == nil || !strings.Contains(err.Error(), "requires WorkDir") {
+		t.Errorf("expected WorkDir error, got %v", err)
+	}
+}
@@ -226,6 +226,7 @@ func Exec(app *cli.MultipleProgram) {
 				Shell:          ctx.String("shell"),
 				Image:          ctx.String("image"),
 				DockerRuntime:  ctx.String("docker-runtime"),
+				TTY:            ctx.Bool("tty"),
 				Memory:         ctx.Int64("memory"),
 				CPU:            ctx.Float64("cpu"),
 				Platform:       ctx.String("platform"),
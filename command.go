@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/go-zoox/command/agent/client"
 	"github.com/go-zoox/command/config"
 	"github.com/go-zoox/command/engine"
 	"github.com/go-zoox/command/engine/host"
+	"github.com/go-zoox/command/engine/namespace"
 	"github.com/go-zoox/command/terminal"
 	"github.com/go-zoox/uuid"
 )
@@ -29,39 +31,99 @@ type Command interface {
 	SetStderr(stderr io.Writer) error
 	//
 	Terminal() (terminal.Terminal, error)
+	//
+	Exec(cfg *Config) (Command, error)
+	//
+	CopyTo(ctx context.Context, src, dst string) error
+	CopyFrom(ctx context.Context, src, dst string) error
+	//
+	Result() *Result
+	Stats() (*Stats, error)
+	Ports() ([]Port, error)
+	//
+	Commit(ref string) (string, error)
+	Cleanup() error
 }
 
 // Config is the command runner config
 type Config = config.Config
 
+// Limits are POSIX resource limits of the command.
+type Limits = config.Limits
+
+// IOLimit is the block I/O limit of a device.
+type IOLimit = config.IOLimit
+
+// ErrCgroupUnavailable is returned when the host engine cannot enforce limits with cgroups v2.
+var ErrCgroupUnavailable = host.ErrCgroupUnavailable
+
+// FSPolicy describes the filesystem access of a command.
+type FSPolicy = config.FSPolicy
+
+// ErrLandlockUnavailable is returned when the host engine cannot enforce the filesystem policy with Landlock.
+var ErrLandlockUnavailable = host.ErrLandlockUnavailable
+
 // New creates a new command runner.
 func New(cfg *Config) (cmd Command, err error) {
-	if cfg.Context == nil {
-		cfg.Context = context.Background()
+	if err := prepare(cfg); err != nil {
+		return nil, err
 	}
 
-	// If sandbox mode is enabled, force docker engine and apply security settings
-	if cfg.Sandbox {
-		if cfg.Engine != "" && cfg.Engine != "docker" {
-			return nil, fmt.Errorf("sandbox mode requires docker engine, but got: %s", cfg.Engine)
+	// support agent
+	if cfg.Agent != "" {
+		if err := checkAgent(cfg); err != nil {
+			return nil, err
+		}
+
+		agent, err := client.New(func(opt *client.Option) {
+			opt.Server = cfg.Agent
+		})
+		if err != nil {
+			return nil, err
 		}
-		cfg.Engine = "docker"
 
-		// Apply default sandbox security settings if not explicitly set
-		if !cfg.DisableNetwork && cfg.Network == "" {
-			// Default to no network in sandbox mode for security
-			cfg.DisableNetwork = true
+		if err := agent.Connect(); err != nil {
+			return nil, err
 		}
-		if cfg.Privileged {
-			// Force non-privileged in sandbox mode
-			cfg.Privileged = false
+
+		err = agent.New(newAgentConfig(cfg))
+		if err != nil {
+			return nil, err
 		}
-		// Set default resource limits if not set
-		if cfg.Memory == 0 {
-			cfg.Memory = 512 // Default 512MB memory limit
+
+		return &agentCommand{
+			Client: agent,
+		}, nil
+	}
+
+	var eg engine.Engine
+	if createEngine, err := engine.Get(cfg.Engine); err != nil {
+		return nil, fmt.Errorf("unsupported command engine: %s", cfg.Engine)
+	} else {
+		eg, err = createEngine(cfg)
+		if err != nil {
+			return nil, err
 		}
-		if cfg.CPU == 0 {
-			cfg.CPU = 1.0 // Default 1 CPU core limit
+	}
+
+	go func() {
+		<-cfg.Context.Done()
+		eg.Cancel()
+	}()
+
+	return newCommand(cfg, eg), nil
+}
+
+// prepare applies the defaults and the sandbox settings to the config.
+func prepare(cfg *Config) error {
+	if cfg.Context == nil {
+		cfg.Context = context.Background()
+	}
+
+	// If sandbox mode is enabled, force docker (or namespace) engine and apply security settings
+	if cfg.Sandbox {
+		if err := applySandbox(cfg); err != nil {
+			return err
 		}
 	}
 
@@ -70,6 +132,78 @@ func New(cfg *Config) (cmd Command, err error) {
 		cfg.Engine = host.Name
 	}
 
+	if cfg.FSPolicy != nil && cfg.Engine != host.Name {
+		return fmt.Errorf("filesystem policy is only supported by the host engine, but got: %s", cfg.Engine)
+	}
+
+	if cfg.Seccomp != "" && !isSeccompSupported(cfg.Engine) {
+		return fmt.Errorf("seccomp is not supported by engine: %s", cfg.Engine)
+	}
+
+	if cfg.Engine == namespace.Name {
+		if err := checkNamespace(cfg); err != nil {
+			return err
+		}
+	}
+
+	if len(cfg.EgressAllow) != 0 {
+		if err := checkEgress(cfg); err != nil {
+			return err
+		}
+	}
+
+	if isNetworkingUsed(cfg) {
+		if err := checkNetworking(cfg); err != nil {
+			return err
+		}
+	}
+
+	if isResourcesUsed(cfg) {
+		if err := checkResources(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Build != nil {
+		if err := checkBuild(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.PullPolicy != "" || len(cfg.RegistryAuths) != 0 {
+		if err := checkPull(cfg); err != nil {
+			return err
+		}
+	}
+
+	if len(cfg.Services) != 0 {
+		if err := checkServices(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.KeepContainer != "" {
+		if err := checkKeepContainer(cfg); err != nil {
+			return err
+		}
+	}
+
+	if len(cfg.Mounts) != 0 {
+		if err := checkMounts(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.ReportChanges {
+		if err := checkChanges(cfg); err != nil {
+			return err
+		}
+	}
+
+	if len(cfg.Artifacts) != 0 && !isArtifactsSupported(cfg.Engine) {
+		return fmt.Errorf("artifacts are not supported by engine: %s", cfg.Engine)
+	}
+
 	if cfg.Shell == "" {
 		cfg.Shell = "/bin/sh"
 	}
@@ -78,6 +212,24 @@ func New(cfg *Config) (cmd Command, err error) {
 		cfg.ID = fmt.Sprintf("go-zoox_command_%s", uuid.V4())
 	}
 
+	if cfg.Language != "" {
+		if err := applyLanguage(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Script != "" {
+		if cfg.Command != "" {
+			return fmt.Errorf("command and script are mutually exclusive")
+		}
+		if !isScriptSupported(cfg.Engine) {
+			return fmt.Errorf("script is not supported by engine: %s", cfg.Engine)
+		}
+		if cfg.Interpreter == "" {
+			cfg.Interpreter = cfg.Shell
+		}
+	}
+
 	environment := map[string]string{
 		"GO_ZOOX_COMMAND_ENGINE":          cfg.Engine,
 		"GO_ZOOX_COMMAND_ID":              cfg.ID,
@@ -101,87 +253,36 @@ func New(cfg *Config) (cmd Command, err error) {
 	}
 	cfg.Environment = environment
 
-	// support agent
-	if cfg.Agent != "" {
-		agent, err := client.New(func(opt *client.Option) {
-			opt.Server = cfg.Agent
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		if err := agent.Connect(); err != nil {
-			return nil, err
-		}
+	return nil
+}
 
-		err = agent.New(&config.Config{
-			// Context:                          cfg.Context,
-			Timeout:                          cfg.Timeout,
-			Engine:                           cfg.Engine,
-			Sandbox:                          cfg.Sandbox,
-			Command:                          cfg.Command,
-			WorkDir:                          cfg.WorkDir,
-			Environment:                      environment,
-			User:                             cfg.User,
-			Shell:                            cfg.Shell,
-			ReadOnly:                         cfg.ReadOnly,
-			IsHistoryDisabled:                cfg.IsHistoryDisabled,
-			Image:                            cfg.Image,
-			Memory:                           cfg.Memory,
-			CPU:                              cfg.CPU,
-			Platform:                         cfg.Platform,
-			Network:                          cfg.Network,
-			DisableNetwork:                   cfg.DisableNetwork,
-			Privileged:                       cfg.Privileged,
-			DockerHost:                       cfg.DockerHost,
-			Server:                           cfg.Server,
-			ClientID:                         cfg.ClientID,
-			ClientSecret:                     cfg.ClientSecret,
-			SSHHost:                          cfg.SSHHost,
-			SSHPort:                          cfg.SSHPort,
-			SSHUser:                          cfg.SSHUser,
-			SSHPass:                          cfg.SSHPass,
-			SSHPrivateKey:                    cfg.SSHPrivateKey,
-			SSHPrivateKeySecret:              cfg.SSHPrivateKeySecret,
-			SSHIsIgnoreStrictHostKeyChecking: cfg.SSHIsIgnoreStrictHostKeyChecking,
-			SSHKnowHostsFilePath:             cfg.SSHKnowHostsFilePath,
-			K8sKubeconfig:                    cfg.K8sKubeconfig,
-			K8sNamespace:                     cfg.K8sNamespace,
-			K8sImage:                         cfg.K8sImage,
-			K8sPodTimeoutSeconds:             cfg.K8sPodTimeoutSeconds,
-			PodmanHost:                       cfg.PodmanHost,
-			ID:                               cfg.ID,
-		})
-		if err != nil {
-			return nil, err
-		}
+type command struct {
+	cfg *Config
+	//
+	engine engine.Engine
+	//
+	result *Result
+	//
+	startedAt time.Time
+	//
+	snapshotDir string
+	snapshot    snapshot
+	//
+	compile *compileOutput
+}
 
-		return agent, nil
+func newCommand(cfg *Config, eg engine.Engine) *command {
+	c := &command{
+		cfg:    cfg,
+		engine: eg,
 	}
 
-	var eg engine.Engine
-	if createEngine, err := engine.Get(cfg.Engine); err != nil {
-		return nil, fmt.Errorf("unsupported command engine: %s", cfg.Engine)
-	} else {
-		eg, err = createEngine(cfg)
-		if err != nil {
-			return nil, err
+	if cfg.Language != "" {
+		// the markers of the compile step are only accepted on stderr
+		if c.compile = newCompileOutput(cfg); c.compile != nil {
+			eg.SetStderr(c.compile.wrap(os.Stderr))
 		}
 	}
 
-	go func() {
-		<-cfg.Context.Done()
-		eg.Cancel()
-	}()
-
-	return &command{
-		cfg:    cfg,
-		engine: eg,
-	}, nil
-}
-
-type command struct {
-	cfg *Config
-	//
-	engine engine.Engine
+	return c
 }
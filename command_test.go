@@ -244,7 +244,7 @@ func TestSandboxMode_RejectNonDockerEngine(t *testing.T) {
 		t.Fatal("expected error when using non-docker engine with sandbox mode")
 	}
 
-	expectedError := "sandbox mode requires docker engine"
+	expectedError := "sandbox mode requires docker, namespace, podman or k8s engine"
 	if !strings.Contains(err.Error(), expectedError) {
 		t.Errorf("expected error to contain %q, got %q", expectedError, err.Error())
 	}
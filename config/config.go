@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"io"
+	"os"
 	"time"
 )
 
@@ -18,6 +20,8 @@ type Config struct {
 	// Sandbox enables sandbox mode for untrusted code execution
 	// When enabled, automatically uses docker engine with strict security settings
 	Sandbox bool
+	// SandboxProfile is the name of the sandbox profile (strict, standard, permissive or a registered one), default: standard
+	SandboxProfile string
 
 	// engine common
 	Command     string
@@ -27,12 +31,42 @@ type Config struct {
 	Shell       string
 	// ReadOnly means none-interactive for terminal, which is used for show log, like top
 	ReadOnly bool
+	// TTY allocates a pseudo-TTY for the container (docker, podman, dind), which merges stderr into stdout,
+	// otherwise stdout and stderr are separated; Terminal always allocates one
+	TTY bool
+
+	// Script is written to a file, staged into the environment and run by Interpreter instead of Command
+	Script string
+	// Interpreter runs the Script, e.g. python3, node or `bash -euo pipefail`, default: Shell
+	Interpreter string
+
+	// Language is the preset (python, node, go, java, bash or a registered one) the Script is
+	// the source code of. It selects the Image, stages the source, compiles and runs it.
+	Language string
+	// CompileTimeout is the timeout of the compile step of the Language, default: the one of the preset
+	CompileTimeout time.Duration
 
 	// engine = host
 	IsHistoryDisabled           bool
 	IsInheritEnvironmentEnabled bool
 	//
 	AllowedSystemEnvKeys []string
+	// CgroupParent is the cgroup v2 path, relative to /sys/fs/cgroup, in which the host engine
+	// creates the cgroup of the command to enforce limits, default: the cgroup of the current process
+	CgroupParent string
+
+	// Limits are the POSIX resource limits of the command (host engine, linux)
+	Limits Limits
+	// FSPolicy restricts the filesystem access of the command (host engine, linux Landlock)
+	FSPolicy *FSPolicy
+	// Seccomp is the seccomp profile of the command (host, docker, podman): a preset
+	// (default, strict, no-network), a Docker-format JSON profile or the path of one
+	Seccomp string
+
+	// PidsLimit is the maximum number of processes, 0 means unlimited
+	PidsLimit int64
+	// IOLimits are the block I/O limits per device
+	IOLimits []IOLimit
 
 	// engine = docker
 	Image string
@@ -40,12 +74,50 @@ type Config struct {
 	Memory int64
 	// CPU is the CPU limit, unit: core
 	CPU float64
+	// MemorySwap is the limit of memory plus swap, unit: MB, -1 means unlimited swap (docker, podman)
+	MemorySwap int64
+	// MemoryReservation is the soft memory limit, unit: MB, which is the memory request on k8s (docker, podman, k8s)
+	MemoryReservation int64
+	// CPUSet are the CPUs the command can run on, e.g. 0-3 or 0,2 (docker, podman)
+	CPUSet string
+	// ShmSize is the size of /dev/shm, unit: MB (docker, podman, k8s)
+	ShmSize int64
+	// Ulimits are the resource limits of the processes in the container (docker, podman)
+	Ulimits []Ulimit
+	// BlkioWeight is the relative block I/O weight, from 10 to 1000 (docker, podman)
+	BlkioWeight uint16
+	// StorageSize is the size limit of the writable layer, unit: MB, which is the ephemeral-storage
+	// limit on k8s. Docker requires a storage driver supporting it, e.g. overlay2 on xfs with pquota (docker, podman, k8s)
+	StorageSize int64
 	// Platform is the command platform, available: linux/amd64, linux/arm64
 	Platform string
 	// Network is the network name
 	Network string
 	// DisableNetwork disables network
 	DisableNetwork bool
+	// Networks are the additional networks the container is connected to (docker, podman)
+	Networks []string
+	// NetworkAliases are the aliases of the container on its networks (docker, podman)
+	NetworkAliases []string
+	// Hostname is the hostname of the container (docker, podman), default: go-zoox
+	Hostname string
+	// Ports are the published ports as [ip:][hostPort:]containerPort[/protocol], e.g. 8080:80,
+	// 127.0.0.1::5432 or 53/udp. A random host port is bound if it is omitted (docker, podman).
+	Ports []string
+	// DNS are the DNS servers of the container (docker, podman)
+	DNS []string
+	// ExtraHosts are the additional /etc/hosts entries of the container as host:ip (docker, podman)
+	ExtraHosts []string
+	// KeepContainer keeps the container after the command exits, always or on-failure,
+	// until Cleanup is called (docker, podman, dind)
+	KeepContainer string
+	// Services are auxiliary containers, e.g. a database, started on a private network of the run
+	// before the command and removed after it (docker, podman)
+	Services []Service
+	// EgressAllow are the only hosts (example.com, *.example.com), networks (10.0.0.0/8) and
+	// optional ports (example.com:443) the command can connect to, through a filtering proxy
+	// (docker, podman, namespace). It takes precedence over DisableNetwork.
+	EgressAllow []string
 	// Privileged enables privileged mode
 	Privileged bool
 	// DockerHost is the Docker host
@@ -56,8 +128,18 @@ type Config struct {
 	ImageRegistryUsername string
 	// ImageRegistryPassword is the Docker image registry password
 	ImageRegistryPassword string
+	// PullPolicy is the image pull policy: Always, IfNotPresent or Never, default: IfNotPresent (docker, podman, dind, k8s)
+	PullPolicy string
+	// Build builds the image of the command before it runs, instead of Image (docker, podman)
+	Build *Build
+	// RegistryAuths are the credentials of the image registries, looked up before ~/.docker/config.json (docker, podman, dind)
+	RegistryAuths []RegistryAuth
 	// DockerRuntime is the container runtime (e.g. runsc for gVisor, kata for Kata Containers)
 	DockerRuntime string
+	// Mounts are the bind mounts, volumes and tmpfs mounts of the container (docker, podman, dind, k8s)
+	Mounts []Mount
+	// DisableWorkDirMount does not bind-mount WorkDir onto itself (docker, dind)
+	DisableWorkDirMount bool
 
 	// engine = caas
 	// Server is the command server address
@@ -99,4 +181,176 @@ type Config struct {
 	DataDirOuter string
 	// DataDirInner is the inner data directory
 	DataDirInner string
+
+	// Artifacts are glob patterns relative to WorkDir, collected after the command exits.
+	// `**` matches any number of directories, a matched directory is collected recursively.
+	Artifacts []string
+	// ArtifactsDir is the local directory the artifacts are copied into
+	ArtifactsDir string
+	// ArtifactsWriter receives the artifacts as a tar.gz stream
+	ArtifactsWriter io.Writer
+	// ArtifactsMaxSize is the limit of the total artifacts size, unit: byte, 0 means unlimited
+	ArtifactsMaxSize int64
+	// ArtifactsMaxFileSize is the limit of a single artifact size, unit: byte, 0 means unlimited
+	ArtifactsMaxFileSize int64
+
+	// ReportChanges reports the filesystem changes of the command in the Result: the container
+	// layer (docker, podman) and the working directory (host, namespace, docker bind mount)
+	ReportChanges bool
+}
+
+// KeepContainer values.
+const (
+	KeepAlways    = "always"
+	KeepOnFailure = "on-failure"
+)
+
+// Image pull policies.
+const (
+	PullAlways       = "Always"
+	PullIfNotPresent = "IfNotPresent"
+	PullNever        = "Never"
+)
+
+// Build describes the image a command runs in, built before it starts.
+type Build struct {
+	// Dockerfile is the content of the Dockerfile
+	Dockerfile string
+	// DockerfilePath is the path of the Dockerfile relative to Context, default: Dockerfile
+	DockerfilePath string
+	// Context is the local directory of the build context, empty for an inline Dockerfile without one
+	Context string
+	// Args are the build args
+	Args map[string]string
+	// Target is the stage to build, default: the last one
+	Target string
+}
+
+// RegistryAuth is the credential of an image registry.
+type RegistryAuth struct {
+	// Registry is the registry host, e.g. ghcr.io, docker.io for Docker Hub
+	Registry string
+	// Username is the user name
+	Username string
+	// Password is the password or access token
+	Password string
+	// IdentityToken is an OAuth identity token used instead of the user name and password
+	IdentityToken string
+}
+
+// Service is an auxiliary container started next to the command.
+type Service struct {
+	// Name is the host name of the service on the private network of the run
+	Name string
+	// Image is the image of the service
+	Image string
+	// Command overrides the command of the image
+	Command []string
+	// Environment is the environment of the service
+	Environment map[string]string
+	// HealthCheck is a shell command run in the service until it succeeds, before the command starts
+	HealthCheck string
+	// HealthTimeout is the time to wait for the service to be healthy, default: 60s
+	HealthTimeout time.Duration
+}
+
+// Mount types.
+const (
+	MountBind   = "bind"
+	MountVolume = "volume"
+	MountTmpfs  = "tmpfs"
+)
+
+// Mount is a mount of a container.
+type Mount struct {
+	// Type is bind, volume or tmpfs, default: bind if Source is an absolute path, volume otherwise
+	Type string
+	// Source is the host path of a bind mount or the name of a volume (k8s: a PersistentVolumeClaim).
+	// A volume without name is anonymous (k8s: an emptyDir).
+	Source string
+	// Target is the absolute path in the container
+	Target string
+	// ReadOnly mounts it read-only
+	ReadOnly bool
+	// TmpfsSize is the size of a tmpfs mount, unit: byte, 0 means the engine default
+	TmpfsSize int64
+	// TmpfsMode is the file mode of a tmpfs mount, e.g. 01777 (not supported by k8s)
+	TmpfsMode os.FileMode
+}
+
+// IOLimit is the block I/O limit of a device. Zero values mean unlimited.
+type IOLimit struct {
+	// Device is the path of the block device, e.g. /dev/sda
+	Device string
+	// ReadBPS is the read rate limit, unit: bytes per second
+	ReadBPS int64
+	// WriteBPS is the write rate limit, unit: bytes per second
+	WriteBPS int64
+	// ReadIOPS is the read rate limit, unit: operations per second
+	ReadIOPS int64
+	// WriteIOPS is the write rate limit, unit: operations per second
+	WriteIOPS int64
+}
+
+// SandboxProfile describes the security settings and resource defaults of sandbox mode.
+type SandboxProfile struct {
+	// Name is the name the profile is registered with
+	Name string
+	// Capabilities are the capabilities kept after dropping all
+	Capabilities []string
+	// ReadOnlyRootfs mounts the root filesystem read-only
+	ReadOnlyRootfs bool
+	// Tmpfs are the tmpfs mounts, path to mount options, e.g. rw,noexec,nosuid,size=100m
+	Tmpfs map[string]string
+	// User is the user (uid[:gid]) the command runs as, unless set in Config
+	User string
+	// PidsLimit is the maximum number of processes, unless set in Config
+	PidsLimit int64
+	// Network is the network policy: none, opt-in (disabled unless Config.Network is set) or allow
+	Network string
+	// Memory is the memory limit, unit: MB, unless set in Config
+	Memory int64
+	// CPU is the CPU limit, unit: core, unless set in Config
+	CPU float64
+	// Seccomp is the seccomp profile, unless set in Config
+	Seccomp string
+}
+
+// FSPolicy describes the filesystem access of a command. Paths not covered are
+// not accessible; relative paths are resolved against WorkDir.
+type FSPolicy struct {
+	// ReadOnly are the paths which can be read and executed, default: /
+	ReadOnly []string
+	// ReadWrite are the paths which can be read and written, WorkDir is always included
+	ReadWrite []string
+	// Deny are the paths which cannot be accessed at all, even below a ReadOnly or ReadWrite path
+	Deny []string
+	// IsFallbackEnabled runs the command without restrictions when Landlock is unavailable, instead of failing
+	IsFallbackEnabled bool
+}
+
+// Ulimit is a resource limit of the processes in a container, e.g. nofile.
+type Ulimit struct {
+	// Name is the name of the limit without the RLIMIT_ prefix, e.g. nofile, nproc or core
+	Name string
+	// Soft is the soft limit
+	Soft int64
+	// Hard is the hard limit
+	Hard int64
+}
+
+// Limits are POSIX resource limits (setrlimit). Zero values mean unlimited.
+type Limits struct {
+	// CPU is the CPU time limit (RLIMIT_CPU), unit: second, exceeding it sends SIGXCPU
+	CPU int64
+	// AddressSpace is the virtual memory limit (RLIMIT_AS), unit: byte
+	AddressSpace int64
+	// OpenFiles is the limit of open file descriptors (RLIMIT_NOFILE)
+	OpenFiles int64
+	// Processes is the limit of processes of the user (RLIMIT_NPROC)
+	Processes int64
+	// FileSize is the limit of the size of written files (RLIMIT_FSIZE), unit: byte, exceeding it sends SIGXFSZ
+	FileSize int64
+	// DisableCoreDump disables core dumps (RLIMIT_CORE = 0)
+	DisableCoreDump bool
 }
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/go-zoox/command/archive"
+	"github.com/go-zoox/command/engine"
+)
+
+// CopyTo copies the local file or directory src into the directory dst of the
+// execution environment, creating dst if needed. Relative dst is resolved against WorkDir.
+func (c *command) CopyTo(ctx context.Context, src, dst string) error {
+	copier, ok := c.engine.(engine.Copier)
+	if !ok {
+		return fmt.Errorf("copy is not supported by engine: %s", c.cfg.Engine)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(archive.Tar(pw, src))
+	}()
+	defer pr.Close()
+
+	return copier.CopyTo(ctx, c.resolve(dst), pr)
+}
+
+// CopyFrom copies the file or directory src of the execution environment into
+// the local directory dst, creating dst if needed. Relative src is resolved against WorkDir.
+func (c *command) CopyFrom(ctx context.Context, src, dst string) error {
+	copier, ok := c.engine.(engine.Copier)
+	if !ok {
+		return fmt.Errorf("copy is not supported by engine: %s", c.cfg.Engine)
+	}
+
+	reader, err := copier.CopyFrom(ctx, c.resolve(src))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return archive.Untar(reader, dst)
+}
+
+// resolve resolves p against the working directory of the execution environment.
+func (c *command) resolve(p string) string {
+	if path.IsAbs(p) || c.cfg.WorkDir == "" {
+		return p
+	}
+
+	return path.Join(c.cfg.WorkDir, p)
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCopy_Host(t *testing.T) {
+	workdir := t.TempDir()
+	src := filepath.Join(t.TempDir(), "input")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "data.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, err := New(&Config{
+		Command: "cat input/data.txt > result.txt",
+		WorkDir: workdir,
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	cmd.SetStdout(&strings.Builder{})
+	cmd.SetStderr(&strings.Builder{})
+
+	if err := cmd.CopyTo(context.Background(), src, "."); err != nil {
+		t.Fatalf("CopyTo() failed: %v", err)
+	}
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := cmd.CopyFrom(context.Background(), "result.txt", dst); err != nil {
+		t.Fatalf("CopyFrom() failed: %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dst, "result.txt"))
+	if err != nil {
+		t.Fatalf("failed to read copied file: %v", err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("expected copied content hello, got %q", b)
+	}
+}
@@ -0,0 +1,30 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-zoox/command/egress"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/namespace"
+	"github.com/go-zoox/command/engine/podman"
+)
+
+// checkEgress validates the egress allowlist for the engine.
+func checkEgress(cfg *Config) error {
+	switch cfg.Engine {
+	case docker.Name, podman.Name, namespace.Name:
+	default:
+		return fmt.Errorf("egress allowlist is only supported by docker, podman and namespace engines, but got: %s", cfg.Engine)
+	}
+
+	if cfg.Network != "" {
+		return fmt.Errorf("egress allowlist cannot be combined with network: %s", cfg.Network)
+	}
+	if len(cfg.Networks) != 0 {
+		return fmt.Errorf("egress allowlist cannot be combined with networks: %s", strings.Join(cfg.Networks, ", "))
+	}
+
+	_, err := egress.Parse(cfg.EgressAllow)
+	return err
+}
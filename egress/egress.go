@@ -0,0 +1,186 @@
+// Package egress implements the egress allowlist of sandboxed commands:
+// a policy of allowed hosts, networks and ports and a filtering HTTP and
+// SOCKS5 proxy enforcing it.
+package egress
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// EventDenied is the type of the event recorded for a denied connection.
+const EventDenied = "egress.denied"
+
+// Policy is a parsed egress allowlist.
+type Policy struct {
+	rules []rule
+}
+
+// rule allows a host name, a domain suffix or a network, on one port or all (0).
+type rule struct {
+	host    string
+	suffix  string
+	network *net.IPNet
+	port    int
+}
+
+// Parse parses the allowlist entries. An entry is a host name (example.com),
+// a wildcard domain matching the subdomains (*.example.com), an IP address or
+// a CIDR (10.0.0.0/8), optionally followed by a port (example.com:443,
+// [2001:db8::/32]:443).
+func Parse(entries []string) (*Policy, error) {
+	p := &Policy{}
+	for _, entry := range entries {
+		r, err := parseRule(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		p.rules = append(p.rules, r)
+	}
+
+	return p, nil
+}
+
+func parseRule(entry string) (rule, error) {
+	r := rule{}
+
+	host := strings.TrimSpace(entry)
+	if strings.HasPrefix(host, "[") {
+		end := strings.Index(host, "]")
+		if end == -1 {
+			return r, fmt.Errorf("egress: invalid entry: %s", entry)
+		}
+
+		rest := host[end+1:]
+		host = host[1:end]
+		if rest != "" {
+			if !strings.HasPrefix(rest, ":") {
+				return r, fmt.Errorf("egress: invalid entry: %s", entry)
+			}
+			if err := r.parsePort(rest[1:]); err != nil {
+				return r, fmt.Errorf("egress: invalid port of %s: %w", entry, err)
+			}
+		}
+	} else if strings.Count(host, ":") == 1 {
+		var port string
+		host, port, _ = strings.Cut(host, ":")
+		if err := r.parsePort(port); err != nil {
+			return r, fmt.Errorf("egress: invalid port of %s: %w", entry, err)
+		}
+	}
+
+	if host == "" {
+		return r, fmt.Errorf("egress: invalid entry: %s", entry)
+	}
+
+	if strings.Contains(host, "/") {
+		_, network, err := net.ParseCIDR(host)
+		if err != nil {
+			return r, fmt.Errorf("egress: invalid network %s: %w", entry, err)
+		}
+		r.network = network
+	} else if ip := net.ParseIP(host); ip != nil {
+		r.network = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+	} else if suffix, ok := strings.CutPrefix(host, "*."); ok {
+		r.suffix = "." + strings.ToLower(suffix)
+	} else {
+		r.host = strings.ToLower(strings.TrimSuffix(host, "."))
+	}
+
+	return r, nil
+}
+
+func (r *rule) parsePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("port out of range: %s", port)
+	}
+
+	r.port = n
+	return nil
+}
+
+func (r *rule) matchPort(port int) bool {
+	return r.port == 0 || r.port == port
+}
+
+// AllowHost reports whether a host name rule allows the host on the port.
+// IP addresses are checked against the network rules instead.
+func (p *Policy) AllowHost(host string, port int) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return p.AllowIP(ip, port)
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, r := range p.rules {
+		if !r.matchPort(port) {
+			continue
+		}
+
+		if r.host != "" && r.host == host {
+			return true
+		}
+		if r.suffix != "" && strings.HasSuffix(host, r.suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowIP reports whether a network rule allows the address on the port.
+func (p *Policy) AllowIP(ip net.IP, port int) bool {
+	for _, r := range p.rules {
+		if r.network != nil && r.matchPort(port) && r.network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasNetworks reports whether the policy has network rules, which host names
+// are resolved for.
+func (p *Policy) hasNetworks() bool {
+	for _, r := range p.rules {
+		if r.network != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Environment adds the variables of a proxy listening on the address to env,
+// unless they are set already. SOCKS clients have to resolve host names through
+// the proxy (socks5h), as the command has no DNS.
+func Environment(env []string, address string) []string {
+	http := "http://" + address
+	socks := "socks5h://" + address
+
+	set := map[string]bool{}
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		set[key] = true
+	}
+
+	for _, kv := range [][2]string{
+		{"HTTP_PROXY", http},
+		{"HTTPS_PROXY", http},
+		{"ALL_PROXY", socks},
+		{"NO_PROXY", "localhost,127.0.0.1"},
+		{"http_proxy", http},
+		{"https_proxy", http},
+		{"all_proxy", socks},
+		{"no_proxy", "localhost,127.0.0.1"},
+	} {
+		if !set[kv[0]] {
+			env = append(env, kv[0]+"="+kv[1])
+		}
+	}
+
+	return env
+}
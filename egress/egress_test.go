@@ -0,0 +1,73 @@
+package egress
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	policy, err := Parse([]string{
+		"pypi.org",
+		"*.pythonhosted.org:443",
+		"10.0.0.0/8",
+		"192.168.1.10:5432",
+		"[2001:db8::/32]:443",
+	})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	for _, tc := range []struct {
+		host  string
+		port  int
+		allow bool
+	}{
+		{"pypi.org", 443, true},
+		{"PyPI.org.", 80, true},
+		{"evil.pypi.org", 443, false},
+		{"files.pythonhosted.org", 443, true},
+		{"files.pythonhosted.org", 80, false},
+		{"pythonhosted.org", 443, false},
+		{"10.1.2.3", 22, true},
+		{"11.1.2.3", 22, false},
+		{"192.168.1.10", 5432, true},
+		{"192.168.1.10", 22, false},
+		{"2001:db8::1", 443, true},
+		{"2001:db9::1", 443, false},
+		{"example.com", 443, false},
+	} {
+		if allow := policy.AllowHost(tc.host, tc.port); allow != tc.allow {
+			t.Errorf("AllowHost(%s, %d) = %v, expected %v", tc.host, tc.port, allow, tc.allow)
+		}
+	}
+
+	if !policy.AllowIP(net.ParseIP("10.255.0.1"), 443) {
+		t.Error("expected 10.255.0.1 to be allowed")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, entry := range []string{"", "example.com:http", "example.com:70000", "10.0.0.0/33", "[::1"} {
+		if _, err := Parse([]string{entry}); err == nil {
+			t.Errorf("expected error for %q", entry)
+		}
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	env := Environment([]string{"PATH=/bin", "NO_PROXY=internal"}, "127.0.0.1:3128")
+
+	values := map[string]string{}
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		values[key] = value
+	}
+
+	if values["HTTPS_PROXY"] != "http://127.0.0.1:3128" || values["all_proxy"] != "socks5h://127.0.0.1:3128" {
+		t.Errorf("unexpected proxy variables: %v", env)
+	}
+	if values["NO_PROXY"] != "internal" || values["PATH"] != "/bin" {
+		t.Errorf("expected the environment to be kept: %v", env)
+	}
+}
@@ -0,0 +1,369 @@
+package egress
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/command/result"
+)
+
+// ErrDenied is the error returned when the policy does not allow a connection.
+var ErrDenied = errors.New("egress denied")
+
+// dialTimeout is the timeout of connecting to an allowed target.
+const dialTimeout = 30 * time.Second
+
+// Proxy is an HTTP (CONNECT and absolute-form requests) and SOCKS5 proxy on a
+// single listener, connecting only to the targets allowed by the policy.
+type Proxy struct {
+	policy *Policy
+	// onDenied is called for every denied connection, e.g. to log it
+	onDenied func(event *result.Event)
+
+	mu       sync.Mutex
+	events   []result.Event
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+}
+
+// NewProxy creates a proxy enforcing the policy.
+func NewProxy(policy *Policy, onDenied func(event *result.Event)) *Proxy {
+	return &Proxy{
+		policy:   policy,
+		onDenied: onDenied,
+		conns:    map[net.Conn]struct{}{},
+	}
+}
+
+// Serve accepts the connections of the listener until the proxy is closed.
+func (p *Proxy) Serve(listener net.Listener) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		listener.Close()
+		return net.ErrClosed
+	}
+	p.listener = listener
+	p.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !p.track(conn) {
+			conn.Close()
+			return nil
+		}
+
+		go func() {
+			defer p.untrack(conn)
+			p.handle(conn)
+		}()
+	}
+}
+
+// Events returns the events of the denied connections.
+func (p *Proxy) Events() []result.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]result.Event(nil), p.events...)
+}
+
+// Close stops accepting connections and closes the open ones.
+func (p *Proxy) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for conn := range p.conns {
+		conn.Close()
+	}
+
+	if p.listener != nil {
+		return p.listener.Close()
+	}
+
+	return nil
+}
+
+func (p *Proxy) track(conn net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return false
+	}
+
+	p.conns[conn] = struct{}{}
+	return true
+}
+
+func (p *Proxy) untrack(conn net.Conn) {
+	p.mu.Lock()
+	delete(p.conns, conn)
+	p.mu.Unlock()
+
+	conn.Close()
+}
+
+// handle serves a client connection, SOCKS5 starts with its version byte.
+func (p *Proxy) handle(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	version, err := reader.Peek(1)
+	if err != nil {
+		return
+	}
+
+	if version[0] == 0x05 {
+		p.handleSOCKS(conn, reader)
+		return
+	}
+
+	p.handleHTTP(conn, reader)
+}
+
+func (p *Proxy) handleHTTP(conn net.Conn, reader *bufio.Reader) {
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		return
+	}
+
+	target := req.Host
+	if req.Method != http.MethodConnect {
+		if req.URL.Host == "" {
+			writeHTTPError(conn, http.StatusBadRequest, "not a proxy request")
+			return
+		}
+
+		target = req.URL.Host
+		if req.URL.Port() == "" {
+			port := "80"
+			if req.URL.Scheme == "https" {
+				port = "443"
+			}
+			target = net.JoinHostPort(req.URL.Hostname(), port)
+		}
+	}
+
+	upstream, err := p.dial(req.Method, target)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, ErrDenied) {
+			status = http.StatusForbidden
+		}
+		writeHTTPError(conn, status, err.Error())
+		return
+	}
+	defer upstream.Close()
+
+	if req.Method == http.MethodConnect {
+		if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+			return
+		}
+	} else {
+		// one request per connection, so every request is checked
+		req.RequestURI = ""
+		req.Close = true
+		req.Header.Del("Proxy-Connection")
+		req.Header.Del("Proxy-Authorization")
+		if err := req.Write(upstream); err != nil {
+			return
+		}
+	}
+
+	tunnel(conn, reader, upstream)
+}
+
+// SOCKS5 reply codes
+const (
+	socksSucceeded           = 0x00
+	socksGeneralFailure      = 0x01
+	socksNotAllowed          = 0x02
+	socksCommandNotSupported = 0x07
+	socksAddressNotSupported = 0x08
+	socksNoAuthentication    = 0x00
+	socksNoAcceptable        = 0xff
+)
+
+func (p *Proxy) handleSOCKS(conn net.Conn, reader *bufio.Reader) {
+	// greeting: version, methods
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, methods); err != nil {
+		return
+	}
+
+	method := byte(socksNoAcceptable)
+	for _, m := range methods {
+		if m == socksNoAuthentication {
+			method = socksNoAuthentication
+		}
+	}
+	if _, err := conn.Write([]byte{0x05, method}); err != nil || method == socksNoAcceptable {
+		return
+	}
+
+	// request: version, command, reserved, address type, address, port
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(reader, request); err != nil {
+		return
+	}
+
+	var host string
+	switch request[3] {
+	case 0x01, 0x04:
+		ip := make(net.IP, 4)
+		if request[3] == 0x04 {
+			ip = make(net.IP, 16)
+		}
+		if _, err := io.ReadFull(reader, ip); err != nil {
+			return
+		}
+		host = ip.String()
+	case 0x03:
+		length, err := reader.ReadByte()
+		if err != nil {
+			return
+		}
+		name := make([]byte, length)
+		if _, err := io.ReadFull(reader, name); err != nil {
+			return
+		}
+		host = string(name)
+	default:
+		writeSOCKSReply(conn, socksAddressNotSupported)
+		return
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(reader, port); err != nil {
+		return
+	}
+
+	if request[1] != 0x01 {
+		writeSOCKSReply(conn, socksCommandNotSupported)
+		return
+	}
+
+	upstream, err := p.dial("SOCKS5", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+	if err != nil {
+		if errors.Is(err, ErrDenied) {
+			writeSOCKSReply(conn, socksNotAllowed)
+		} else {
+			writeSOCKSReply(conn, socksGeneralFailure)
+		}
+		return
+	}
+	defer upstream.Close()
+
+	if err := writeSOCKSReply(conn, socksSucceeded); err != nil {
+		return
+	}
+
+	tunnel(conn, reader, upstream)
+}
+
+// dial connects to the target if the policy allows it. Host names not allowed
+// by name are resolved, and connected to on the first allowed address.
+func (p *Proxy) dial(protocol, target string) (net.Conn, error) {
+	host, portString, err := net.SplitHostPort(target)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port: %s", portString)
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if p.policy.AllowHost(host, port) {
+		return dialer.Dial("tcp", target)
+	}
+
+	if net.ParseIP(host) == nil && p.policy.hasNetworks() {
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		defer cancel()
+
+		addrs, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+		if err == nil {
+			for _, ip := range addrs {
+				if p.policy.AllowIP(ip, port) {
+					return dialer.Dial("tcp", net.JoinHostPort(ip.String(), portString))
+				}
+			}
+		}
+	}
+
+	p.deny(protocol, target)
+	return nil, fmt.Errorf("%w: %s", ErrDenied, target)
+}
+
+func (p *Proxy) deny(protocol, target string) {
+	event := result.Event{
+		Time:    time.Now(),
+		Type:    EventDenied,
+		Target:  target,
+		Message: fmt.Sprintf("denied %s connection to %s", protocol, target),
+	}
+
+	p.mu.Lock()
+	p.events = append(p.events, event)
+	p.mu.Unlock()
+
+	if p.onDenied != nil {
+		p.onDenied(&event)
+	}
+}
+
+// tunnel copies between the client, whose buffered bytes are sent first, and the upstream.
+func tunnel(conn net.Conn, reader *bufio.Reader, upstream net.Conn) {
+	done := make(chan struct{}, 2)
+
+	go func() {
+		io.Copy(upstream, reader)
+		if tcp, ok := upstream.(*net.TCPConn); ok {
+			tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}()
+
+	go func() {
+		io.Copy(conn, upstream)
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}()
+
+	<-done
+	<-done
+}
+
+func writeHTTPError(conn net.Conn, status int, message string) {
+	fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\nContent-Type: text/plain\r\nContent-Length: %d\r\nConnection: close\r\n\r\n%s\n", status, http.StatusText(status), len(message)+1, message)
+}
+
+func writeSOCKSReply(conn net.Conn, code byte) error {
+	_, err := conn.Write([]byte{0x05, code, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+	return err
+}
@@ -0,0 +1,148 @@
+package egress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/command/result"
+)
+
+func startProxy(t *testing.T, entries ...string) (*Proxy, string, *[]string) {
+	t.Helper()
+
+	policy, err := Parse(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	denied := &[]string{}
+	proxy := NewProxy(policy, func(event *result.Event) {
+		*denied = append(*denied, event.Target)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go proxy.Serve(listener)
+	t.Cleanup(func() { proxy.Close() })
+
+	return proxy, listener.Addr().String(), denied
+}
+
+func TestProxy_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello")
+	}))
+	defer server.Close()
+
+	target := strings.TrimPrefix(server.URL, "http://")
+	proxy, address, denied := startProxy(t, target)
+
+	proxyURL, _ := url.Parse("http://" + address)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	response, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if string(body) != "hello" {
+		t.Errorf("expected hello, got %q", body)
+	}
+
+	response, err = client.Get("http://example.com:8080/")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", response.StatusCode)
+	}
+
+	if len(*denied) != 1 || (*denied)[0] != "example.com:8080" {
+		t.Errorf("expected denied example.com:8080, got %v", *denied)
+	}
+	if events := proxy.Events(); len(events) != 1 || events[0].Type != EventDenied {
+		t.Errorf("expected one denied event, got %v", events)
+	}
+}
+
+func TestProxy_CONNECT(t *testing.T) {
+	_, address, _ := startProxy(t, "127.0.0.1")
+
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer echo.Close()
+	go func() {
+		conn, err := echo.Accept()
+		if err == nil {
+			io.Copy(conn, conn)
+			conn.Close()
+		}
+	}()
+
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", echo.Addr(), echo.Addr())
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("connect: %v %v", response, err)
+	}
+
+	fmt.Fprint(conn, "ping\n")
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "ping\n" {
+		t.Errorf("expected echo, got %q %v", line, err)
+	}
+}
+
+func TestProxy_SOCKS5(t *testing.T) {
+	_, address, denied := startProxy(t, "allowed.test:443")
+
+	socks := func(host string, port int) byte {
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		conn.Write([]byte{0x05, 0x01, 0x00})
+		greeting := make([]byte, 2)
+		if _, err := io.ReadFull(conn, greeting); err != nil || greeting[1] != 0x00 {
+			t.Fatalf("greeting: %v %v", greeting, err)
+		}
+
+		request := append([]byte{0x05, 0x01, 0x00, 0x03, byte(len(host))}, host...)
+		request = append(request, byte(port>>8), byte(port))
+		conn.Write(request)
+
+		reply := make([]byte, 10)
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			t.Fatalf("reply: %v", err)
+		}
+		return reply[1]
+	}
+
+	if code := socks("denied.test", 443); code != socksNotAllowed {
+		t.Errorf("expected not allowed, got %d", code)
+	}
+	if len(*denied) != 1 || (*denied)[0] != "denied.test:443" {
+		t.Errorf("expected denied denied.test:443, got %v", *denied)
+	}
+}
@@ -0,0 +1,27 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEgress_Validation(t *testing.T) {
+	for _, tc := range []struct {
+		cfg *Config
+		err string
+	}{
+		{&Config{Command: "echo", Engine: "host", EgressAllow: []string{"example.com"}}, "only supported by docker, podman and namespace"},
+		{&Config{Command: "echo", Engine: "docker", Network: "bridge", EgressAllow: []string{"example.com"}}, "cannot be combined with network"},
+		{&Config{Command: "echo", Engine: "docker", EgressAllow: []string{"example.com:http"}}, "invalid port"},
+	} {
+		err := prepare(tc.cfg)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("expected error containing %q, got %v", tc.err, err)
+		}
+	}
+
+	cfg := &Config{Command: "echo", Sandbox: true, EgressAllow: []string{"pypi.org:443", "*.pythonhosted.org"}}
+	if err := prepare(cfg); err != nil {
+		t.Errorf("prepare: %v", err)
+	}
+}
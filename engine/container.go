@@ -29,3 +29,20 @@ func Get(name string) (func(cfg *config.Config) (Engine, error), error) {
 
 	return e, nil
 }
+
+var sessions = safe.NewMap[string, func(cfg *config.Config) (Session, error)]()
+
+// RegisterSession registers a session factory for an engine.
+func RegisterSession(name string, s func(cfg *config.Config) (Session, error)) error {
+	return sessions.Set(name, s)
+}
+
+// GetSession gets a session factory for an engine.
+func GetSession(name string) (func(cfg *config.Config) (Session, error), error) {
+	s := sessions.Get(name)
+	if s == nil {
+		return nil, ErrEngineNotFound
+	}
+
+	return s, nil
+}
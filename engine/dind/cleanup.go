@@ -0,0 +1,12 @@
+package dind
+
+import "github.com/go-zoox/command/engine"
+
+// Cleanup removes the dind container kept after exit.
+func (d *dind) Cleanup() error {
+	if cleaner, ok := d.client.(engine.Cleaner); ok {
+		return cleaner.Cleanup()
+	}
+
+	return nil
+}
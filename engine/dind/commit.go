@@ -0,0 +1,17 @@
+package dind
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-zoox/command/engine"
+)
+
+// Commit creates the image ref from the dind container kept after exit.
+func (d *dind) Commit(ctx context.Context, ref string) (string, error) {
+	if committer, ok := d.client.(engine.Committer); ok {
+		return committer.Commit(ctx, ref)
+	}
+
+	return "", fmt.Errorf("dind: commit is not supported")
+}
@@ -1,5 +1,7 @@
 package dind
 
+import "github.com/go-zoox/command/config"
+
 // Config is the configuration for a Docker engine.
 type Config struct {
 	Command     string
@@ -9,6 +11,12 @@ type Config struct {
 	Shell       string
 	// ReadOnly means none-interactive for terminal, which is used for show log, like top
 	ReadOnly bool
+	// TTY allocates a pseudo-TTY for the container, which merges stderr into stdout
+	TTY bool
+
+	// Script is staged into the container and run by Interpreter instead of Command
+	Script      string
+	Interpreter string
 
 	// engine = docker
 	Image string
@@ -22,6 +30,14 @@ type Config struct {
 	Network string
 	// DisableNetwork disables network
 	DisableNetwork bool
+	// Mounts are the bind mounts, volumes and tmpfs mounts of the container
+	Mounts []config.Mount
+	// DisableWorkDirMount does not bind-mount WorkDir onto itself
+	DisableWorkDirMount bool
+	// PullPolicy is the image pull policy: Always, IfNotPresent or Never, default: IfNotPresent
+	PullPolicy string
+	// RegistryAuths are the credentials of the image registries, looked up before ~/.docker/config.json
+	RegistryAuths []config.RegistryAuth
 
 	// Custom Command Runner ID
 	ID string
@@ -33,4 +49,7 @@ type Config struct {
 	DataDirOuter string
 	// DataDirInner is the inner data directory
 	DataDirInner string
+
+	// IsAutoRemoveDisabled keeps the container after exit until Cleanup is called
+	IsAutoRemoveDisabled bool
 }
@@ -0,0 +1,29 @@
+package dind
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/go-zoox/command/engine"
+)
+
+// CopyTo extracts content into the directory dst of the dind container.
+func (d *dind) CopyTo(ctx context.Context, dst string, content io.Reader) error {
+	copier, ok := d.client.(engine.Copier)
+	if !ok {
+		return fmt.Errorf("dind: copy is not supported")
+	}
+
+	return copier.CopyTo(ctx, dst, content)
+}
+
+// CopyFrom archives the file or directory src of the dind container.
+func (d *dind) CopyFrom(ctx context.Context, src string) (io.ReadCloser, error) {
+	copier, ok := d.client.(engine.Copier)
+	if !ok {
+		return nil, fmt.Errorf("dind: copy is not supported")
+	}
+
+	return copier.CopyFrom(ctx, src)
+}
@@ -8,6 +8,15 @@ import (
 
 // create creates a container.
 func (d *dind) create() (err error) {
+	d.inheritSystemEnv()
+
+	d.client, err = docker.New(d.dockerConfig())
+
+	return
+}
+
+// inheritSystemEnv copies the allowed system environment variables into the environment.
+func (d *dind) inheritSystemEnv() {
 	if len(d.cfg.AllowedSystemEnvKeys) != 0 {
 		for _, key := range d.cfg.AllowedSystemEnvKeys {
 			if d.cfg.Environment[key] == "" {
@@ -17,8 +26,11 @@ func (d *dind) create() (err error) {
 			}
 		}
 	}
+}
 
-	d.client, err = docker.New(&docker.Config{
+// dockerConfig returns the configuration of the underlying docker engine.
+func (d *dind) dockerConfig() *docker.Config {
+	return &docker.Config{
 		ID: d.cfg.ID,
 		//
 		Command:        d.cfg.Command,
@@ -27,6 +39,9 @@ func (d *dind) create() (err error) {
 		User:           d.cfg.User,
 		Shell:          d.cfg.Shell,
 		ReadOnly:       d.cfg.ReadOnly,
+		TTY:            d.cfg.TTY,
+		Script:         d.cfg.Script,
+		Interpreter:    d.cfg.Interpreter,
 		Image:          d.cfg.Image,
 		Memory:         d.cfg.Memory,
 		CPU:            d.cfg.CPU,
@@ -35,9 +50,15 @@ func (d *dind) create() (err error) {
 		DisableNetwork: d.cfg.DisableNetwork,
 		Privileged:     true,
 		//
+		Mounts:              d.cfg.Mounts,
+		DisableWorkDirMount: d.cfg.DisableWorkDirMount,
+		//
+		PullPolicy:    d.cfg.PullPolicy,
+		RegistryAuths: d.cfg.RegistryAuths,
+		//
 		DataDirOuter: d.cfg.DataDirOuter,
 		DataDirInner: d.cfg.DataDirInner,
-	})
-
-	return
+		//
+		IsAutoRemoveDisabled: d.cfg.IsAutoRemoveDisabled,
+	}
 }
@@ -0,0 +1,17 @@
+package dind
+
+import (
+	"fmt"
+
+	"github.com/go-zoox/command/engine"
+)
+
+// Exec creates a process running inside the dind container.
+func (d *dind) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	execer, ok := d.client.(engine.Execer)
+	if !ok {
+		return nil, fmt.Errorf("dind: exec is not supported")
+	}
+
+	return execer.Exec(cfg)
+}
@@ -0,0 +1,18 @@
+package dind
+
+import (
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/docker"
+)
+
+// NewSession creates a new dind session, backed by a privileged docker session.
+func NewSession(cfg *Config) (engine.Session, error) {
+	cfg.Image = "whatwewant/dind:v24-1"
+
+	d := &dind{
+		cfg: cfg,
+	}
+	d.inheritSystemEnv()
+
+	return docker.NewSession(d.dockerConfig())
+}
@@ -0,0 +1,18 @@
+package dind
+
+import (
+	"fmt"
+
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/result"
+)
+
+// Stats returns the resource usage of the dind container.
+func (d *dind) Stats() (*result.Stats, error) {
+	reader, ok := d.client.(engine.StatsReader)
+	if !ok {
+		return nil, fmt.Errorf("dind: stats are not supported")
+	}
+
+	return reader.Stats()
+}
@@ -8,7 +8,12 @@ import (
 
 // Cancel cancels the command.
 func (d *docker) Cancel() error {
+	defer d.egress.Remove(d.container.ID)
+	defer d.services.Remove(d.container.ID)
+	defer d.oom.Stop()
+
 	return d.client.ContainerRemove(context.Background(), d.container.ID, container.RemoveOptions{
-		Force: true,
+		Force:         true,
+		RemoveVolumes: true,
 	})
 }
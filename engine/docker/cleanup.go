@@ -0,0 +1,15 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Cleanup removes the container kept after exit.
+func (d *docker) Cleanup() error {
+	return d.client.ContainerRemove(context.Background(), d.container.ID, container.RemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	})
+}
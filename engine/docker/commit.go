@@ -0,0 +1,19 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Commit creates the image ref from the container kept after exit and returns the image ID.
+func (d *docker) Commit(ctx context.Context, ref string) (string, error) {
+	resp, err := d.client.ContainerCommit(ctx, d.container.ID, container.CommitOptions{
+		Reference: ref,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
@@ -1,5 +1,7 @@
 package docker
 
+import "github.com/go-zoox/command/config"
+
 // Config is the configuration for a Docker engine.
 type Config struct {
 	Command     string
@@ -9,6 +11,12 @@ type Config struct {
 	Shell       string
 	// ReadOnly means none-interactive for terminal, which is used for show log, like top
 	ReadOnly bool
+	// TTY allocates a pseudo-TTY for the container, which merges stderr into stdout
+	TTY bool
+
+	// Script is staged into the container and run by Interpreter instead of Command
+	Script      string
+	Interpreter string
 
 	// engine = docker
 	// Image is the name of the docker image
@@ -17,12 +25,44 @@ type Config struct {
 	Memory int64
 	// CPU is the CPU limit, unit: core
 	CPU float64
+	// PidsLimit is the maximum number of processes
+	PidsLimit int64
+	// MemorySwap is the limit of memory plus swap, unit: MB, -1 means unlimited swap
+	MemorySwap int64
+	// MemoryReservation is the soft memory limit, unit: MB
+	MemoryReservation int64
+	// CPUSet are the CPUs the container can run on, e.g. 0-3 or 0,2
+	CPUSet string
+	// ShmSize is the size of /dev/shm, unit: MB
+	ShmSize int64
+	// Ulimits are the resource limits of the processes in the container
+	Ulimits []config.Ulimit
+	// BlkioWeight is the relative block I/O weight, from 10 to 1000
+	BlkioWeight uint16
+	// StorageSize is the size limit of the writable layer, unit: MB
+	StorageSize int64
 	// Platform is the command platform, available: linux/amd64, linux/arm64
 	Platform string
 	// Network is the network name
 	Network string
 	// DisableNetwork disables network
 	DisableNetwork bool
+	// Networks are the additional networks the container is connected to
+	Networks []string
+	// NetworkAliases are the aliases of the container on its networks
+	NetworkAliases []string
+	// Hostname is the hostname of the container, default: go-zoox
+	Hostname string
+	// Ports are the published ports as [ip:][hostPort:]containerPort[/protocol]
+	Ports []string
+	// DNS are the DNS servers of the container
+	DNS []string
+	// ExtraHosts are the additional /etc/hosts entries of the container as host:ip
+	ExtraHosts []string
+	// Services are the containers started on a private network before the command, and removed after it
+	Services []config.Service
+	// EgressAllow are the only hosts, networks and ports reachable through the egress proxy
+	EgressAllow []string
 	//
 	Privileged bool
 	// DockerHost is the Docker host address
@@ -33,8 +73,18 @@ type Config struct {
 	ImageRegistryUsername string
 	// ImageRegistryPassword is the Docker image registry password
 	ImageRegistryPassword string
+	// PullPolicy is the image pull policy: Always, IfNotPresent or Never, default: IfNotPresent
+	PullPolicy string
+	// Build builds the image of the command before it runs, instead of Image
+	Build *config.Build
+	// RegistryAuths are the credentials of the image registries, looked up before ~/.docker/config.json
+	RegistryAuths []config.RegistryAuth
 	// Runtime is the container runtime (e.g. runsc for gVisor)
 	Runtime string
+	// Mounts are the bind mounts, volumes and tmpfs mounts of the container
+	Mounts []config.Mount
+	// DisableWorkDirMount does not bind-mount WorkDir onto itself
+	DisableWorkDirMount bool
 
 	// Custom Command Runner ID
 	ID string
@@ -49,4 +99,11 @@ type Config struct {
 
 	// Sandbox enables strict security settings for untrusted code
 	Sandbox bool
+	// SandboxProfile is the profile of sandbox mode, default: standard
+	SandboxProfile *config.SandboxProfile
+	// Seccomp is the seccomp profile: a preset (default, strict, no-network), a Docker-format JSON profile or its path
+	Seccomp string
+
+	// IsAutoRemoveDisabled keeps the container after exit until Cleanup is called
+	IsAutoRemoveDisabled bool
 }
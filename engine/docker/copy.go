@@ -0,0 +1,18 @@
+package docker
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+)
+
+// CopyTo extracts content into the directory dst of the command container.
+func (d *docker) CopyTo(ctx context.Context, dst string, content io.Reader) error {
+	return dockerapi.CopyTo(ctx, d.client, d.container.ID, Name, dst, content)
+}
+
+// CopyFrom archives the file or directory src of the command container.
+func (d *docker) CopyFrom(ctx context.Context, src string) (io.ReadCloser, error) {
+	return dockerapi.CopyFrom(ctx, d.client, d.container.ID, Name, src)
+}
@@ -13,6 +13,10 @@ import (
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+	"github.com/go-zoox/command/pull"
+	"github.com/go-zoox/command/sandbox"
+	"github.com/go-zoox/command/seccomp"
 	"github.com/go-zoox/core-utils/cast"
 	"github.com/go-zoox/core-utils/strings"
 	"github.com/go-zoox/datetime"
@@ -21,6 +25,17 @@ import (
 
 // create creates a container.
 func (d *docker) create() (err error) {
+	defer func() {
+		if err != nil {
+			d.services.Remove(d.container.ID)
+			d.egress.Remove(d.container.ID)
+		}
+	}()
+
+	if d.cfg.Script != "" {
+		d.cfg.Command = d.scriptCommand()
+	}
+
 	if d.cfg.Command != "" {
 		d.args = append(d.args, "-c", d.cfg.Command)
 	}
@@ -54,13 +69,12 @@ func (d *docker) create() (err error) {
 	}
 
 	cfg := &container.Config{
-		Hostname:     "go-zoox",
 		Image:        d.cfg.Image,
 		Cmd:          append([]string{d.cfg.Shell}, d.args...),
 		User:         d.cfg.User,
 		WorkingDir:   d.cfg.WorkDir,
 		Env:          d.env,
-		Tty:          true,
+		Tty:          d.cfg.TTY,
 		OpenStdin:    true,
 		AttachStdin:  true,
 		AttachStdout: true,
@@ -70,7 +84,7 @@ func (d *docker) create() (err error) {
 
 	hostCfg := &container.HostConfig{
 		// auto remove container
-		AutoRemove: true,
+		AutoRemove: !d.cfg.IsAutoRemoveDisabled,
 		//
 		Resources: container.Resources{
 			// Memory:    d.cfg.Memory,
@@ -94,6 +108,13 @@ func (d *docker) create() (err error) {
 
 	// Apply sandbox security settings
 	if d.cfg.Sandbox {
+		profile := d.cfg.SandboxProfile
+		if profile == nil {
+			if profile, err = sandbox.Get(sandbox.Standard); err != nil {
+				return err
+			}
+		}
+
 		// Force non-privileged mode
 		hostCfg.Privileged = false
 
@@ -102,42 +123,36 @@ func (d *docker) create() (err error) {
 			"no-new-privileges:true",
 		}
 
-		// Drop all dangerous capabilities, keep only necessary ones
+		// Drop all capabilities first, keep only the ones of the profile
 		hostCfg.CapDrop = []string{
-			"ALL", // Drop all capabilities first
-		}
-		hostCfg.CapAdd = []string{
-			"CHOWN",
-			"DAC_OVERRIDE",
-			"FOWNER",
-			"FSETID",
-			"KILL",
-			"SETGID",
-			"SETUID",
-			"SETPCAP",
-			"NET_BIND_SERVICE",
-			"NET_RAW",
-			"SYS_CHROOT",
-			"MKNOD",
-			"AUDIT_WRITE",
-			"SETFCAP",
+			"ALL",
 		}
+		hostCfg.CapAdd = profile.Capabilities
 
-		// Read-only root filesystem (can be overridden with tmpfs for /tmp)
-		hostCfg.ReadonlyRootfs = true
+		hostCfg.ReadonlyRootfs = profile.ReadOnlyRootfs
 
-		// Add tmpfs mounts for writable directories
-		hostCfg.Tmpfs = map[string]string{
-			"/tmp":     "rw,noexec,nosuid,size=100m",
-			"/var/tmp": "rw,noexec,nosuid,size=100m",
-		}
+		// tmpfs mounts for writable directories
+		hostCfg.Tmpfs = profile.Tmpfs
 
 		// Default to no network if not explicitly configured
-		if !d.cfg.DisableNetwork && d.cfg.Network == "" {
+		if profile.Network != sandbox.NetworkAllow && !d.cfg.DisableNetwork && d.cfg.Network == "" && len(d.cfg.Networks) == 0 {
 			hostCfg.NetworkMode = "none"
 		}
 
-		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] sandbox mode enabled with strict security settings\n", datetime.Now().Format())))
+		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] sandbox mode enabled with %s profile\n", datetime.Now().Format(), profile.Name)))
+	}
+
+	if d.cfg.Seccomp != "" {
+		opt, err := seccomp.SecurityOpt(d.cfg.Seccomp)
+		if err != nil {
+			d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to load seccomp profile: %s\n", datetime.Now().Format(), err)))
+			return err
+		}
+
+		// the default preset is applied by docker itself
+		if opt != "" {
+			hostCfg.SecurityOpt = append(hostCfg.SecurityOpt, opt)
+		}
 	}
 
 	if d.cfg.Memory != 0 {
@@ -146,12 +161,16 @@ func (d *docker) create() (err error) {
 	if d.cfg.DisableNetwork {
 		hostCfg.NetworkMode = "none"
 	}
+	if d.cfg.PidsLimit != 0 {
+		hostCfg.Resources.PidsLimit = &d.cfg.PidsLimit
+	}
+	d.applyResources(hostCfg)
 
 	if d.cfg.CPU != 0 {
 		hostCfg.Resources.CPUPeriod = 100000
 		hostCfg.Resources.CPUQuota = cast.ToInt64(float64(hostCfg.Resources.CPUPeriod) * d.cfg.CPU)
 	}
-	if d.cfg.WorkDir != "" {
+	if d.cfg.WorkDir != "" && !d.cfg.DisableWorkDirMount {
 		hostCfg.Mounts = append(hostCfg.Mounts, mount.Mount{
 			Type:     mount.TypeBind,
 			Source:   d.cfg.WorkDir,
@@ -159,6 +178,10 @@ func (d *docker) create() (err error) {
 			ReadOnly: false,
 		})
 	}
+	if d.cfg.Script != "" {
+		hostCfg.Mounts = append(hostCfg.Mounts, scriptMount())
+	}
+	hostCfg.Mounts = append(hostCfg.Mounts, dockerapi.Mounts(d.cfg.Mounts)...)
 	// data directory
 	if d.cfg.DataDirOuter != "" && d.cfg.DataDirInner != "" {
 		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] mount data directory: %s -> %s ...\n", datetime.Now().Format(), d.cfg.DataDirOuter, d.cfg.DataDirInner)))
@@ -181,16 +204,14 @@ func (d *docker) create() (err error) {
 	networkCfg := &network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{},
 	}
-	if d.cfg.Network != "" {
-		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] inspect network %s ...\n", datetime.Now().Format(), d.cfg.Network)))
-		networkIns, err := d.client.NetworkInspect(context.Background(), d.cfg.Network, network.InspectOptions{})
-		if err != nil {
-			d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to inspect network: %s\n", datetime.Now().Format(), err)))
-			return err
-		}
+	if err := dockerapi.ApplyNetwork(d.client, d.network(), Name, d.stderr, cfg, hostCfg, networkCfg); err != nil {
+		return err
+	}
 
-		networkCfg.EndpointsConfig[d.cfg.Network] = &network.EndpointSettings{
-			NetworkID: networkIns.ID,
+	if len(d.cfg.EgressAllow) != 0 {
+		d.egress = dockerapi.NewEgress(d.client, d.cfg.ID, d.cfg.EgressAllow, Name, d.stderr)
+		if err := d.egress.Create(cfg, hostCfg, networkCfg); err != nil {
+			return err
 		}
 	}
 
@@ -213,60 +234,127 @@ func (d *docker) create() (err error) {
 
 	// Check if docker registry credentials are provided via config or environment variables
 	// Priority: config > environment variables
-	dockerRegistry := d.cfg.ImageRegistry
-	dockerRegistryUsername := d.cfg.ImageRegistryUsername
-	dockerRegistryPassword := d.cfg.ImageRegistryPassword
-
-	// Fallback to environment variables if not set in config
-	if dockerRegistry == "" {
-		dockerRegistry = os.Getenv("DOCKER_REGISTRY")
-	}
-	if dockerRegistryUsername == "" {
-		dockerRegistryUsername = os.Getenv("DOCKER_REGISTRY_USERNAME")
-	}
-	if dockerRegistryPassword == "" {
-		dockerRegistryPassword = os.Getenv("DOCKER_REGISTRY_PASSWORD")
-	}
-
-	if dockerRegistry != "" && dockerRegistryUsername != "" && dockerRegistryPassword != "" {
-		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] login to registry %s ...\n", datetime.Now().Format(), dockerRegistry)))
+	d.registryAuths = append(d.registryAuths, d.cfg.RegistryAuths...)
+	if auth := dockerapi.ImageRegistryAuth(d.cfg.ImageRegistry, d.cfg.ImageRegistryUsername, d.cfg.ImageRegistryPassword); auth != nil {
+		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] login to registry %s ...\n", datetime.Now().Format(), auth.Registry)))
 		authConfig := registry.AuthConfig{
-			Username:      dockerRegistryUsername,
-			Password:      dockerRegistryPassword,
-			ServerAddress: dockerRegistry,
+			Username:      auth.Username,
+			Password:      auth.Password,
+			ServerAddress: auth.Registry,
 		}
 		_, err := d.client.RegistryLogin(context.Background(), authConfig)
 		if err != nil {
-			d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to login to registry %s: %s\n", datetime.Now().Format(), dockerRegistry, err)))
+			d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to login to registry %s: %s\n", datetime.Now().Format(), auth.Registry, err)))
 			return err
 		}
-		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] login to registry %s success\n", datetime.Now().Format(), dockerRegistry)))
+		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] login to registry %s success\n", datetime.Now().Format(), auth.Registry)))
+
+		// the login only checks the credential, pulls pass it themselves
+		d.registryAuths = append(d.registryAuths, *auth)
 	}
 
-	_, _, err = d.client.ImageInspectWithRaw(context.Background(), d.cfg.Image)
-	if err != nil {
-		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] pull image %s ...\n", datetime.Now().Format(), d.cfg.Image)))
-		imagePullReader, err := d.client.ImagePull(context.Background(), d.cfg.Image, image.PullOptions{
-			Platform: d.cfg.Platform,
-		})
-		if err != nil {
-			d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to pull image %s ...\n", datetime.Now().Format(), err)))
+	if d.cfg.Build != nil {
+		if d.cfg.Image, err = dockerapi.BuildImage(d.client, d.cfg.Build, d.cfg.Platform, d.cfg.PullPolicy, d.registryAuths, Name, d.stderr); err != nil {
 			return err
 		}
-		defer imagePullReader.Close()
+		cfg.Image = d.cfg.Image
+	} else if err := d.pullImage(d.cfg.Image); err != nil {
+		return err
+	}
 
-		if err := jsonmessage.DisplayJSONMessagesToStream(imagePullReader, streams.NewOut(d.stderr), nil); err != nil {
+	if len(d.cfg.Services) != 0 {
+		d.services = dockerapi.NewServices(d.client, d.cfg.ID, d.cfg.Services, d.pullImage, Name, d.stderr)
+		if err := d.services.Create(hostCfg, networkCfg); err != nil {
 			return err
 		}
 	}
 
-	d.container, err = d.client.ContainerCreate(context.Background(), cfg, hostCfg, networkCfg, platformCfg, d.cfg.ID)
+	d.containerCfg, d.hostCfg, d.networkCfg, d.platformCfg = cfg, hostCfg, networkCfg, platformCfg
+	if err := d.createContainer(); err != nil {
+		return err
+	}
+
+	d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] succeed to prepare docker environment.\n", datetime.Now().Format())))
+
+	return nil
+}
+
+// createContainer creates the container from the prepared configs, attached to
+// its networks and services, with the script staged.
+func (d *docker) createContainer() (err error) {
+	d.container, err = d.client.ContainerCreate(context.Background(), d.containerCfg, d.hostCfg, d.networkCfg, d.platformCfg, d.cfg.ID)
 	if err != nil {
 		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to create container: %s\n", datetime.Now().Format(), err)))
 		return err
 	}
 
-	d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] succeed to prepare docker environment.\n", datetime.Now().Format())))
+	if err := dockerapi.ConnectNetworks(d.client, d.network(), d.container.ID, Name, d.stderr); err != nil {
+		d.client.ContainerRemove(context.Background(), d.container.ID, container.RemoveOptions{Force: true})
+		return err
+	}
+
+	if err := d.services.Connect(d.hostCfg, d.container.ID); err != nil {
+		d.client.ContainerRemove(context.Background(), d.container.ID, container.RemoveOptions{Force: true})
+		return err
+	}
+
+	if d.cfg.Script != "" {
+		if err := d.stageScript(); err != nil {
+			d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to stage script: %s\n", datetime.Now().Format(), err)))
+			d.client.ContainerRemove(context.Background(), d.container.ID, container.RemoveOptions{Force: true})
+			return err
+		}
+	}
 
 	return nil
 }
+
+// allocateTTY recreates the container with a pseudo-TTY, which a terminal requires,
+// if it was created without one.
+func (d *docker) allocateTTY() error {
+	if d.containerCfg.Tty {
+		return nil
+	}
+
+	if err := d.client.ContainerRemove(context.Background(), d.container.ID, container.RemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	}); err != nil {
+		return err
+	}
+
+	d.containerCfg.Tty = true
+	return d.createContainer()
+}
+
+// pullImage pulls the image as required by the pull policy, with the credential of its registry.
+func (d *docker) pullImage(ref string) error {
+	_, _, err := d.client.ImageInspectWithRaw(context.Background(), ref)
+	required, err := pull.IsRequired(d.cfg.PullPolicy, err == nil)
+	if err != nil {
+		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] %s: %s\n", datetime.Now().Format(), err, ref)))
+		return fmt.Errorf("%w: %s", err, ref)
+	}
+	if !required {
+		return nil
+	}
+
+	auth, err := pull.Auth(ref, d.registryAuths)
+	if err != nil {
+		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to resolve registry credential: %s\n", datetime.Now().Format(), err)))
+		return err
+	}
+
+	d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] pull image %s ...\n", datetime.Now().Format(), ref)))
+	imagePullReader, err := d.client.ImagePull(context.Background(), ref, image.PullOptions{
+		Platform:     d.cfg.Platform,
+		RegistryAuth: auth,
+	})
+	if err != nil {
+		d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to pull image %s ...\n", datetime.Now().Format(), err)))
+		return err
+	}
+	defer imagePullReader.Close()
+
+	return jsonmessage.DisplayJSONMessagesToStream(imagePullReader, streams.NewOut(d.stderr), nil)
+}
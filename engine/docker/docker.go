@@ -6,9 +6,14 @@ import (
 	"os"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/go-zoox/command/config"
 	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+	"github.com/go-zoox/command/result"
 	"github.com/go-zoox/uuid"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // Name is the name of the engine.
@@ -25,6 +30,28 @@ type docker struct {
 	client *client.Client
 	//
 	container container.CreateResponse
+	// the configs the container is created from
+	containerCfg *container.Config
+	hostCfg      *container.HostConfig
+	networkCfg   *network.NetworkingConfig
+	platformCfg  *ocispec.Platform
+	// waitC and waitErrC are registered before the container starts, so the exit code
+	// is not lost if the container is auto removed before Wait is called
+	waitC    <-chan container.WaitResponse
+	waitErrC <-chan error
+	// oom watches the oom event of the container
+	oom *dockerapi.OOMWatcher
+	//
+	registryAuths []config.RegistryAuth
+	//
+	// output is closed when the output of a container without TTY is copied
+	output chan struct{}
+	//
+	stats *dockerapi.StatsSampler
+	ports []result.Port
+	//
+	egress   *dockerapi.Egress
+	services *dockerapi.Services
 
 	//
 	stdin  io.Reader
@@ -0,0 +1,14 @@
+package docker
+
+import (
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+)
+
+// ExecTerminal is a terminal for a process executed inside a container.
+type ExecTerminal = dockerapi.ExecTerminal
+
+// Exec creates a process running inside the command container.
+func (d *docker) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	return dockerapi.NewProcess(d.client, d.container.ID, Name, cfg), nil
+}
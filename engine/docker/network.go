@@ -0,0 +1,29 @@
+package docker
+
+import (
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+	"github.com/go-zoox/command/result"
+)
+
+// network returns the network configuration of the container.
+func (d *docker) network() *dockerapi.Network {
+	return &dockerapi.Network{
+		Hostname:       d.cfg.Hostname,
+		Ports:          d.cfg.Ports,
+		DNS:            d.cfg.DNS,
+		ExtraHosts:     d.cfg.ExtraHosts,
+		Network:        d.cfg.Network,
+		Networks:       d.cfg.Networks,
+		NetworkAliases: d.cfg.NetworkAliases,
+	}
+}
+
+// Ports returns the published ports with their bound host ports, once started.
+func (d *docker) Ports() []result.Port {
+	return d.ports
+}
+
+// Events returns the connections denied by the egress proxy.
+func (d *docker) Events() []result.Event {
+	return d.egress.Events()
+}
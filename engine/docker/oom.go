@@ -0,0 +1,8 @@
+package docker
+
+import "github.com/go-zoox/command/engine/internal/dockerapi"
+
+// isOOMKilled reports whether the OOM killer killed a process of the container.
+func (d *docker) isOOMKilled() bool {
+	return dockerapi.IsOOMKilled(d.client, d.container.ID, d.cfg.IsAutoRemoveDisabled, d.oom)
+}
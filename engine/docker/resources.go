@@ -0,0 +1,19 @@
+package docker
+
+import (
+	"github.com/docker/docker/api/types/container"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+)
+
+// applyResources applies the resource controls beyond memory, CPU and pids to the container.
+func (d *docker) applyResources(hostCfg *container.HostConfig) {
+	dockerapi.ApplyResources(hostCfg, &dockerapi.Resources{
+		MemorySwap:        d.cfg.MemorySwap,
+		MemoryReservation: d.cfg.MemoryReservation,
+		CPUSet:            d.cfg.CPUSet,
+		ShmSize:           d.cfg.ShmSize,
+		Ulimits:           d.cfg.Ulimits,
+		BlkioWeight:       d.cfg.BlkioWeight,
+		StorageSize:       d.cfg.StorageSize,
+	})
+}
@@ -0,0 +1,31 @@
+package docker
+
+import (
+	"context"
+	"path"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/go-zoox/command/engine"
+)
+
+// scriptDir is an anonymous volume holding the staged script, which stays
+// writable with a read-only root filesystem and is removed with the container.
+const scriptDir = "/.go-zoox-command"
+
+// scriptMount returns the mount of the script directory.
+func scriptMount() mount.Mount {
+	return mount.Mount{
+		Type:   mount.TypeVolume,
+		Target: scriptDir,
+	}
+}
+
+// scriptCommand returns the command running the staged script.
+func (d *docker) scriptCommand() string {
+	return engine.ScriptCommand(d.cfg.Interpreter, path.Join(scriptDir, engine.ScriptFileName))
+}
+
+// stageScript copies the script into the created container.
+func (d *docker) stageScript() error {
+	return d.CopyTo(context.Background(), scriptDir, engine.ScriptArchive(d.cfg.Script))
+}
@@ -0,0 +1,28 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+)
+
+// NewSession creates a new docker session, which keeps one container running
+// and executes every command inside it with the exec API.
+func NewSession(cfg *Config) (engine.Session, error) {
+	cfg.Command = dockerapi.SessionKeepAliveCommand
+
+	e, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	d := e.(*docker)
+	if err := d.client.ContainerStart(context.Background(), d.container.ID, container.StartOptions{}); err != nil {
+		d.Cancel()
+		return nil, err
+	}
+
+	return dockerapi.NewSession(d.client, d.container.ID, Name, d.Cancel), nil
+}
@@ -2,10 +2,15 @@ package docker
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+	"github.com/go-zoox/datetime"
 )
 
 // Start starts the command.
@@ -21,23 +26,38 @@ func (d *docker) Start() error {
 		return err
 	}
 
-	if err := applyStdin(stream.Conn, d.stdin); err != nil {
-		return nil
-	}
+	if d.containerCfg.Tty {
+		if err := applyStdin(stream.Conn, d.stdin); err != nil {
+			return nil
+		}
 
-	if err := applyStdout(stream.Conn, d.stdout); err != nil {
-		return nil
-	}
+		if err := applyStdout(stream.Conn, d.stdout); err != nil {
+			return nil
+		}
 
-	if err := applyStderr(stream.Conn, d.stderr); err != nil {
-		return nil
+		if err := applyStderr(stream.Conn, d.stderr); err != nil {
+			return nil
+		}
+	} else {
+		d.output = demux(stream, d.stdin, d.stdout, d.stderr)
 	}
 
+	d.oom = dockerapi.WatchOOM(d.client, d.container.ID)
+	d.waitC, d.waitErrC = d.client.ContainerWait(context.Background(), d.container.ID, d.waitCondition())
+
 	err = d.client.ContainerStart(context.Background(), d.container.ID, container.StartOptions{})
 	if err != nil {
 		return err
 	}
 
+	d.stats = dockerapi.SampleStats(d.client, d.container.ID, Name)
+
+	if len(d.cfg.Ports) != 0 {
+		if d.ports, err = dockerapi.InspectPorts(d.client, d.container.ID, Name); err != nil {
+			d.stderr.Write([]byte(fmt.Sprintf("[%s][docker] failed to inspect ports: %s\n", datetime.Now().Format(), err)))
+		}
+	}
+
 	return nil
 }
 
@@ -60,3 +80,32 @@ func applyStdout(conn net.Conn, stdout io.Writer) error {
 func applyStderr(conn net.Conn, stderr io.Writer) error {
 	return nil
 }
+
+// demux copies stdin to a container without TTY, closing its stdin at EOF, and
+// demultiplexes its output into stdout and stderr. The channel is closed when the
+// output ends.
+func demux(stream types.HijackedResponse, stdin io.Reader, stdout, stderr io.Writer) chan struct{} {
+	if stdin != nil {
+		go func() {
+			io.Copy(stream.Conn, stdin)
+			stream.CloseWrite()
+		}()
+	} else {
+		stream.CloseWrite()
+	}
+
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stdcopy.StdCopy(stdout, stderr, stream.Reader)
+	}()
+
+	return done
+}
@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func TestDemux(t *testing.T) {
+	client, server := net.Pipe()
+	stream := types.HijackedResponse{Conn: client, Reader: bufio.NewReader(client)}
+
+	var stdout, stderr bytes.Buffer
+	done := demux(stream, strings.NewReader(""), &stdout, &stderr)
+
+	go func() {
+		// the stdin of the container is closed at EOF
+		io.Copy(io.Discard, server)
+	}()
+	go func() {
+		stdcopy.NewStdWriter(server, stdcopy.Stdout).Write([]byte("out\n"))
+		stdcopy.NewStdWriter(server, stdcopy.Stderr).Write([]byte("err\n"))
+		server.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the output to end")
+	}
+
+	if stdout.String() != "out\n" {
+		t.Errorf("expected stdout %q, got %q", "out\n", stdout.String())
+	}
+	if stderr.String() != "err\n" {
+		t.Errorf("expected stderr %q, got %q", "err\n", stderr.String())
+	}
+}
+
+func TestDemux_ClosesStdinWithoutReader(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	server, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	stream := types.HijackedResponse{Conn: client, Reader: bufio.NewReader(client)}
+	done := demux(stream, nil, nil, nil)
+
+	// the container sees EOF on its stdin right away
+	server.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if n, err := server.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Fatalf("expected EOF on stdin, got %d bytes, %v", n, err)
+	}
+
+	server.Close()
+	<-done
+}
@@ -0,0 +1,16 @@
+package docker
+
+import (
+	"errors"
+
+	"github.com/go-zoox/command/result"
+)
+
+// Stats returns the resource usage of the container from the docker stats stream.
+func (d *docker) Stats() (*result.Stats, error) {
+	if d.stats == nil {
+		return nil, errors.New("container is not started")
+	}
+
+	return d.stats.Get()
+}
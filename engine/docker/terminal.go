@@ -15,6 +15,10 @@ import (
 
 // Terminal returns a terminal.
 func (d *docker) Terminal() (terminal.Terminal, error) {
+	if err := d.allocateTTY(); err != nil {
+		return nil, err
+	}
+
 	stream, err := d.client.ContainerAttach(context.Background(), d.container.ID, container.AttachOptions{
 		Stream: true,
 		Stdin:  true,
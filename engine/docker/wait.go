@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/go-zoox/command/errors"
@@ -11,7 +12,23 @@ import (
 
 // Wait waits for the command to finish.
 func (d *docker) Wait() error {
-	result, err := d.client.ContainerWait(context.Background(), d.container.ID, container.WaitConditionNotRunning)
+	defer d.egress.Remove(d.container.ID)
+	defer d.services.Remove(d.container.ID)
+	defer d.oom.Stop()
+
+	if d.stats != nil {
+		// let the final sample arrive before the container is gone
+		defer d.stats.Wait(time.Second)
+	}
+
+	result, err := d.waitC, d.waitErrC
+	if result == nil {
+		result, err = d.client.ContainerWait(context.Background(), d.container.ID, container.WaitConditionNotRunning)
+	}
+
+	// flush the demultiplexed output before returning, it ends when the container exits
+	defer d.flushOutput()
+
 	select {
 	case err := <-err:
 		if err != nil && err != io.EOF {
@@ -19,6 +36,15 @@ func (d *docker) Wait() error {
 		}
 	case result := <-result:
 		if result.StatusCode != 0 {
+			if d.isOOMKilled() {
+				return &errors.ExitError{
+					Code:      int(result.StatusCode),
+					Message:   fmt.Sprintf("container exited with non-zero status: %d: killed by the OOM killer (memory limit: %dMB)", result.StatusCode, d.cfg.Memory),
+					OOMKilled: true,
+					Reason:    errors.ReasonOOMKilled,
+				}
+			}
+
 			// return fmt.Errorf("container exited with non-zero status: %d", result.StatusCode)
 			return &errors.ExitError{
 				Code:    int(result.StatusCode),
@@ -29,3 +55,20 @@ func (d *docker) Wait() error {
 
 	return nil
 }
+
+// waitCondition returns the condition to wait for before the container starts:
+// an auto removed container is waited until removal, a kept one until its next exit.
+func (d *docker) waitCondition() container.WaitCondition {
+	if d.cfg.IsAutoRemoveDisabled {
+		return container.WaitConditionNextExit
+	}
+
+	return container.WaitConditionRemoved
+}
+
+// flushOutput waits until the output of a container without TTY is copied.
+func (d *docker) flushOutput() {
+	if d.output != nil {
+		<-d.output
+	}
+}
@@ -1,8 +1,10 @@
 package engine
 
 import (
+	"context"
 	"io"
 
+	"github.com/go-zoox/command/result"
 	"github.com/go-zoox/command/terminal"
 )
 
@@ -18,3 +20,56 @@ type Engine interface {
 	//
 	Terminal() (terminal.Terminal, error)
 }
+
+// Execer is implemented by engines that can run additional processes inside
+// the environment of a running command.
+type Execer interface {
+	Exec(cfg *ExecConfig) (Engine, error)
+}
+
+// Copier is implemented by engines that can copy files in and out of the
+// execution environment. Content is exchanged as tar streams.
+type Copier interface {
+	// CopyTo extracts the tar stream content into the directory dst,
+	// creating it if it does not exist.
+	CopyTo(ctx context.Context, dst string, content io.Reader) error
+	// CopyFrom returns a tar stream of the file or directory src,
+	// with a single top-level entry named after src.
+	CopyFrom(ctx context.Context, src string) (io.ReadCloser, error)
+}
+
+// Cleaner is implemented by engines whose environment outlives the command
+// when automatic removal is disabled, e.g. a stopped container.
+type Cleaner interface {
+	Cleanup() error
+}
+
+// Committer is implemented by engines that can snapshot the environment of the
+// command into an image, e.g. a container kept after exit.
+type Committer interface {
+	Commit(ctx context.Context, ref string) (string, error)
+}
+
+// StatsReader is implemented by engines that can report the resource usage of
+// the command, sampled while it runs and summarized once it exits.
+type StatsReader interface {
+	Stats() (*result.Stats, error)
+}
+
+// Differ is implemented by engines that can report the changes the command made
+// to its environment outside of the working directory, e.g. the container layer.
+type Differ interface {
+	Diff(ctx context.Context) ([]result.Change, error)
+}
+
+// PortReader is implemented by engines that publish ports of the command,
+// bound once it starts.
+type PortReader interface {
+	Ports() []result.Port
+}
+
+// EventReader is implemented by engines that record events while the command
+// runs, e.g. the connections denied by the egress allowlist.
+type EventReader interface {
+	Events() []result.Event
+}
@@ -2,10 +2,24 @@ package host
 
 // Cancel cancels the command.
 func (h *host) Cancel() error {
+	defer h.removeScript()
+
 	if h.cmd.Process == nil {
+		if h.landlock != nil {
+			h.landlock.close()
+		}
+
+		if h.cgroup != nil {
+			h.cgroup.remove()
+		}
+
 		return nil
 	}
 
+	if h.cgroup != nil {
+		h.cgroup.kill()
+	}
+
 	if err := h.cmd.Process.Kill(); err != nil {
 		return err
 	}
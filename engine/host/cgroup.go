@@ -0,0 +1,7 @@
+package host
+
+import "errors"
+
+// ErrCgroupUnavailable is returned when limits are requested but the command
+// cannot be placed in its own cgroup v2.
+var ErrCgroupUnavailable = errors.New("cgroup v2 delegation is unavailable")
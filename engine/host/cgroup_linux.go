@@ -0,0 +1,301 @@
+package host
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-zoox/command/result"
+	"golang.org/x/sys/unix"
+)
+
+// cgroupRoot is the mount point of the cgroup v2 hierarchy.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupHint explains how to make delegation available.
+const cgroupHint = "run inside a delegated cgroup (e.g. systemd-run --user --scope -p Delegate=yes) or set CgroupParent to a writable cgroup without processes"
+
+// cgroup is the cgroup v2 the command runs in.
+type cgroup struct {
+	path string
+	dir  *os.File
+}
+
+// newCgroup creates the cgroup of the command under the parent and applies the limits.
+func newCgroup(cfg *Config) (*cgroup, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("%w: cgroup v2 is not mounted at %s", ErrCgroupUnavailable, cgroupRoot)
+	}
+
+	parent := cfg.CgroupParent
+	if parent == "" {
+		current, err := currentCgroup()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCgroupUnavailable, err)
+		}
+		parent = current
+	}
+	parentPath := filepath.Join(cgroupRoot, filepath.Clean("/"+parent))
+
+	controllers := []string{}
+	if cfg.Memory != 0 {
+		controllers = append(controllers, "memory")
+	}
+	if cfg.CPU != 0 {
+		controllers = append(controllers, "cpu")
+	}
+	if cfg.PidsLimit != 0 {
+		controllers = append(controllers, "pids")
+	}
+	if len(cfg.IOLimits) != 0 {
+		controllers = append(controllers, "io")
+	}
+	if err := enableControllers(parentPath, controllers); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(parentPath, fmt.Sprintf("go-zoox-command-%s", cfg.ID))
+	if err := os.Mkdir(path, 0755); err != nil {
+		return nil, fmt.Errorf("%w: %v; %s", ErrCgroupUnavailable, err, cgroupHint)
+	}
+
+	cg := &cgroup{path: path}
+	if err := cg.apply(cfg); err != nil {
+		cg.remove()
+		return nil, err
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		cg.remove()
+		return nil, err
+	}
+	cg.dir = dir
+
+	return cg, nil
+}
+
+// currentCgroup returns the cgroup v2 path of the current process.
+func currentCgroup() (string, error) {
+	b, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return path, nil
+		}
+	}
+
+	return "", errors.New("the current process is not in a cgroup v2")
+}
+
+// enableControllers enables the controllers for the children of the parent cgroup.
+func enableControllers(parentPath string, controllers []string) error {
+	b, err := os.ReadFile(filepath.Join(parentPath, "cgroup.controllers"))
+	if err != nil {
+		return fmt.Errorf("%w: %v; %s", ErrCgroupUnavailable, err, cgroupHint)
+	}
+
+	available := strings.Fields(string(b))
+	enable := []string{}
+	for _, controller := range controllers {
+		found := false
+		for _, v := range available {
+			if v == controller {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: controller %s is not delegated to %s; %s", ErrCgroupUnavailable, controller, parentPath, cgroupHint)
+		}
+
+		enable = append(enable, "+"+controller)
+	}
+
+	if len(enable) == 0 {
+		return nil
+	}
+
+	if err := os.WriteFile(filepath.Join(parentPath, "cgroup.subtree_control"), []byte(strings.Join(enable, " ")), 0644); err != nil {
+		return fmt.Errorf("%w: failed to enable controllers in %s: %v; %s", ErrCgroupUnavailable, parentPath, err, cgroupHint)
+	}
+
+	return nil
+}
+
+// apply writes the limits into the cgroup files.
+func (cg *cgroup) apply(cfg *Config) error {
+	if cfg.Memory != 0 {
+		if err := cg.write("memory.max", strconv.FormatInt(cfg.Memory*1024*1024, 10)); err != nil {
+			return err
+		}
+	}
+
+	if cfg.CPU != 0 {
+		period := int64(100000)
+		if err := cg.write("cpu.max", fmt.Sprintf("%d %d", int64(float64(period)*cfg.CPU), period)); err != nil {
+			return err
+		}
+	}
+
+	if cfg.PidsLimit != 0 {
+		if err := cg.write("pids.max", strconv.FormatInt(cfg.PidsLimit, 10)); err != nil {
+			return err
+		}
+	}
+
+	for _, limit := range cfg.IOLimits {
+		var stat unix.Stat_t
+		if err := unix.Stat(limit.Device, &stat); err != nil {
+			return fmt.Errorf("failed to stat io device %s: %v", limit.Device, err)
+		}
+
+		line := fmt.Sprintf("%d:%d", unix.Major(uint64(stat.Rdev)), unix.Minor(uint64(stat.Rdev)))
+		for _, kv := range []struct {
+			key   string
+			value int64
+		}{
+			{"rbps", limit.ReadBPS},
+			{"wbps", limit.WriteBPS},
+			{"riops", limit.ReadIOPS},
+			{"wiops", limit.WriteIOPS},
+		} {
+			if kv.value != 0 {
+				line += fmt.Sprintf(" %s=%d", kv.key, kv.value)
+			}
+		}
+
+		if err := cg.write("io.max", line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cg *cgroup) write(file, value string) error {
+	if err := os.WriteFile(filepath.Join(cg.path, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to set %s: %v", file, err)
+	}
+
+	return nil
+}
+
+// attach makes the command start inside the cgroup.
+func (cg *cgroup) attach(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(cg.dir.Fd())
+}
+
+// stats reads the accounting of the cgroup.
+func (cg *cgroup) stats() (*result.Stats, error) {
+	stats := &result.Stats{}
+
+	cpu, err := cg.readKeyValues("cpu.stat")
+	if err != nil {
+		return nil, err
+	}
+	stats.UserTime = time.Duration(cpu["user_usec"]) * time.Microsecond
+	stats.SystemTime = time.Duration(cpu["system_usec"]) * time.Microsecond
+	stats.CPUTime = time.Duration(cpu["usage_usec"]) * time.Microsecond
+
+	if v, err := cg.readInt("memory.current"); err == nil {
+		stats.Memory = v
+	}
+	// memory.peak is available since linux 5.19
+	if v, err := cg.readInt("memory.peak"); err == nil {
+		stats.PeakMemory = v
+	}
+
+	if b, err := os.ReadFile(filepath.Join(cg.path, "io.stat")); err == nil {
+		for _, line := range strings.Split(string(b), "\n") {
+			for _, field := range strings.Fields(line) {
+				key, value, _ := strings.Cut(field, "=")
+				n, _ := strconv.ParseInt(value, 10, 64)
+				switch key {
+				case "rbytes":
+					stats.BlockRead += n
+				case "wbytes":
+					stats.BlockWrite += n
+				}
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// isOOMKilled reports whether the OOM killer killed a process of the cgroup.
+func (cg *cgroup) isOOMKilled() bool {
+	events, err := cg.readKeyValues("memory.events")
+	if err != nil {
+		return false
+	}
+
+	return events["oom_kill"] > 0
+}
+
+func (cg *cgroup) readInt(file string) (int64, error) {
+	b, err := os.ReadFile(filepath.Join(cg.path, file))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+}
+
+func (cg *cgroup) readKeyValues(file string) (map[string]int64, error) {
+	f, err := os.Open(filepath.Join(cg.path, file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := map[string]int64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			values[fields[0]], _ = strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+
+	return values, scanner.Err()
+}
+
+// kill kills every process of the cgroup, available since linux 5.14.
+func (cg *cgroup) kill() error {
+	return cg.write("cgroup.kill", "1")
+}
+
+// remove kills the remaining processes and removes the cgroup.
+func (cg *cgroup) remove() {
+	if cg.dir != nil {
+		cg.dir.Close()
+	}
+
+	cg.kill()
+
+	// the cgroup can only be removed once the killed processes are gone
+	for i := 0; i < 50; i++ {
+		if err := os.Remove(cg.path); err == nil || os.IsNotExist(err) {
+			return
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+}
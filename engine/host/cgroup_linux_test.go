@@ -0,0 +1,74 @@
+package host
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew_CgroupLimits(t *testing.T) {
+	eng, err := New(&Config{
+		ID:        "test-cgroup-limits",
+		Command:   "cat /proc/self/cgroup",
+		Memory:    64,
+		PidsLimit: 32,
+	})
+	if err != nil {
+		// delegation is not available everywhere, but it has to fail clearly
+		if !errors.Is(err, ErrCgroupUnavailable) {
+			t.Fatalf("expected ErrCgroupUnavailable, got %v", err)
+		}
+		t.Skip(err)
+	}
+
+	buf := &strings.Builder{}
+	eng.SetStdout(buf)
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := eng.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "go-zoox-command-test-cgroup-limits") {
+		t.Errorf("expected the command in its own cgroup, got %q", buf.String())
+	}
+}
+
+func TestCgroup_Stats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"cpu.stat":       "usage_usec 3000000\nuser_usec 2000000\nsystem_usec 1000000\n",
+		"memory.current": "1048576\n",
+		"memory.peak":    "4194304\n",
+		"memory.events":  "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n",
+		"io.stat":        "8:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cg := &cgroup{path: dir}
+	stats, err := cg.stats()
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+
+	if stats.CPUTime != 3*time.Second || stats.UserTime != 2*time.Second || stats.SystemTime != time.Second {
+		t.Errorf("unexpected cpu times: %+v", stats)
+	}
+	if stats.Memory != 1<<20 || stats.PeakMemory != 4<<20 {
+		t.Errorf("unexpected memory: %+v", stats)
+	}
+	if stats.BlockRead != 4096 || stats.BlockWrite != 8192 {
+		t.Errorf("unexpected block io: %+v", stats)
+	}
+	if !cg.isOOMKilled() {
+		t.Error("expected oom_kill to be reported")
+	}
+}
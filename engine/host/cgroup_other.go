@@ -0,0 +1,33 @@
+//go:build !linux
+
+package host
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/go-zoox/command/result"
+)
+
+// cgroup is only available on linux.
+type cgroup struct{}
+
+func newCgroup(cfg *Config) (*cgroup, error) {
+	return nil, fmt.Errorf("%w: cgroups are only supported on linux", ErrCgroupUnavailable)
+}
+
+func (cg *cgroup) attach(cmd *exec.Cmd) {}
+
+func (cg *cgroup) stats() (*result.Stats, error) {
+	return nil, ErrCgroupUnavailable
+}
+
+func (cg *cgroup) isOOMKilled() bool {
+	return false
+}
+
+func (cg *cgroup) kill() error {
+	return nil
+}
+
+func (cg *cgroup) remove() {}
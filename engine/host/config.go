@@ -1,5 +1,7 @@
 package host
 
+import "github.com/go-zoox/command/config"
+
 // Config is the configuration for a host engine.
 type Config struct {
 	Command     string
@@ -10,6 +12,10 @@ type Config struct {
 	// ReadOnly means none-interactive for terminal, which is used for show log, like top
 	ReadOnly bool
 
+	// Script is written to a temporary file and run by Interpreter instead of Command
+	Script      string
+	Interpreter string
+
 	//
 	IsHistoryDisabled bool
 	//
@@ -19,4 +25,28 @@ type Config struct {
 
 	// Custom Command Runner ID
 	ID string
+
+	// Memory is the memory limit, unit: MB, enforced with cgroups v2 on linux
+	Memory int64
+	// CPU is the CPU limit, unit: core, enforced with cgroups v2 on linux
+	CPU float64
+	// PidsLimit is the maximum number of processes, enforced with cgroups v2 on linux
+	PidsLimit int64
+	// IOLimits are the block I/O limits, enforced with cgroups v2 on linux
+	IOLimits []config.IOLimit
+	// CgroupParent is the cgroup v2 path, relative to /sys/fs/cgroup, to create the command cgroup in
+	CgroupParent string
+
+	// Limits are the POSIX resource limits applied to the command before it runs, linux only
+	Limits config.Limits
+
+	// FSPolicy restricts the filesystem access of the command with Landlock, linux only
+	FSPolicy *config.FSPolicy
+	// Seccomp is the seccomp profile: a preset (default, strict, no-network), a Docker-format JSON profile or its path, linux only
+	Seccomp string
+}
+
+// isCgroupRequired reports whether limits have to be enforced with a cgroup.
+func (c *Config) isCgroupRequired() bool {
+	return c.Memory != 0 || c.CPU != 0 || c.PidsLimit != 0 || len(c.IOLimits) != 0
 }
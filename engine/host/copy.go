@@ -0,0 +1,23 @@
+package host
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-zoox/command/archive"
+)
+
+// CopyTo extracts content into the directory dst on the host.
+func (h *host) CopyTo(ctx context.Context, dst string, content io.Reader) error {
+	return archive.Untar(content, dst)
+}
+
+// CopyFrom archives the file or directory src on the host.
+func (h *host) CopyFrom(ctx context.Context, src string) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(archive.Tar(pw, src))
+	}()
+
+	return pr, nil
+}
@@ -15,13 +15,25 @@ func (h *host) create() error {
 		return errors.New("command: already created")
 	}
 
-	args := []string{}
-	if h.cfg.Command != "" {
-		args = append(args, "-c", h.cfg.Command)
+	if err := h.stageScript(); err != nil {
+		return err
+	}
+
+	h.cmd = exec.Command(h.cfg.Shell)
+
+	command := h.cfg.Command
+	if h.cfg.hasLimits() {
+		var err error
+		if command, err = h.gateLimits(command); err != nil {
+			return err
+		}
 	}
 
-	logger.Debugf("create command: %s %v", h.cfg.Shell, args)
-	h.cmd = exec.Command(h.cfg.Shell, args...)
+	if command != "" {
+		h.cmd.Args = append(h.cmd.Args, "-c", command)
+	}
+
+	logger.Debugf("create command: %s %v", h.cfg.Shell, h.cmd.Args[1:])
 
 	if err := applyEnv(h.cmd, h.cfg.Environment, h.cfg.IsInheritEnvironmentEnabled, h.cfg.AllowedSystemEnvKeys); err != nil {
 		return err
@@ -39,6 +51,38 @@ func (h *host) create() error {
 		return err
 	}
 
+	if h.cfg.FSPolicy != nil {
+		l, err := h.newLandlock()
+		if err != nil {
+			if !errors.Is(err, ErrLandlockUnavailable) || !h.cfg.FSPolicy.IsFallbackEnabled {
+				return err
+			}
+
+			logger.Warnf("filesystem policy is not enforced: %s", err)
+		}
+
+		h.landlock = l
+	}
+
+	if h.cfg.Seccomp != "" {
+		s, err := h.newSeccomp()
+		if err != nil {
+			return err
+		}
+
+		h.seccomp = s
+	}
+
+	if h.cfg.isCgroupRequired() {
+		cg, err := newCgroup(h.cfg)
+		if err != nil {
+			return err
+		}
+
+		h.cgroup = cg
+		h.cgroup.attach(h.cmd)
+	}
+
 	return nil
 }
 
@@ -43,3 +43,16 @@ func applyUser(cmd *exec.Cmd, username string) error {
 
 	return nil
 }
+
+// ptyAttrs returns the SysProcAttr for starting cmd in a pty. pty.Start replaces
+// SysProcAttr, so the credential and the cgroup are carried over.
+func ptyAttrs(cmd *exec.Cmd) *syscall.SysProcAttr {
+	attrs := &syscall.SysProcAttr{}
+	if cmd.SysProcAttr != nil {
+		*attrs = *cmd.SysProcAttr
+	}
+	attrs.Setsid = true
+	attrs.Setctty = true
+
+	return attrs
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"os/user"
+	"strings"
 	"syscall"
 	"testing"
 )
@@ -137,3 +138,35 @@ func TestKillProcess_ProcessGroup(t *testing.T) {
 		t.Logf("killProcess error: %v (may be expected on some systems)", err)
 	}
 }
+
+func TestScript_ReadableByUser(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("switching the user requires root")
+	}
+	if _, err := user.Lookup("nobody"); err != nil {
+		t.Skipf("user nobody not found: %v", err)
+	}
+
+	eng, err := New(&Config{
+		Script:      "echo script",
+		Interpreter: "/bin/sh",
+		User:        "nobody",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	buf := &strings.Builder{}
+	eng.SetStdout(buf)
+	eng.SetStderr(buf)
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := eng.Wait(); err != nil {
+		t.Fatalf("Wait: %v: %s", err, buf.String())
+	}
+
+	if strings.TrimSpace(buf.String()) != "script" {
+		t.Errorf("expected the script to run as nobody, got %q", buf.String())
+	}
+}
@@ -5,6 +5,7 @@ package host
 import (
 	"os/exec"
 	"os/user"
+	"syscall"
 
 	"github.com/go-zoox/logger"
 )
@@ -32,3 +33,8 @@ func applyUser(cmd *exec.Cmd, username string) error {
 
 	return nil
 }
+
+// ptyAttrs returns the SysProcAttr for starting cmd in a pty.
+func ptyAttrs(cmd *exec.Cmd) *syscall.SysProcAttr {
+	return cmd.SysProcAttr
+}
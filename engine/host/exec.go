@@ -0,0 +1,39 @@
+package host
+
+import "github.com/go-zoox/command/engine"
+
+// Exec creates a plain host process sharing the settings of the command.
+func (h *host) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	return New(h.cfg.exec(cfg, cfg.Command))
+}
+
+// exec creates the config of a process started beside the command, which
+// keeps the resource limits and the filesystem and syscall restrictions.
+func (c *Config) exec(cfg *engine.ExecConfig, command string) *Config {
+	return &Config{
+		ID: c.ID,
+		//
+		Command:     command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		//
+		IsHistoryDisabled:           c.IsHistoryDisabled,
+		IsInheritEnvironmentEnabled: c.IsInheritEnvironmentEnabled,
+		AllowedSystemEnvKeys:        c.AllowedSystemEnvKeys,
+		//
+		Memory:       c.Memory,
+		CPU:          c.CPU,
+		PidsLimit:    c.PidsLimit,
+		IOLimits:     c.IOLimits,
+		CgroupParent: c.CgroupParent,
+		//
+		Limits: c.Limits,
+		//
+		FSPolicy: c.FSPolicy,
+		Seccomp:  c.Seccomp,
+	}
+}
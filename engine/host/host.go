@@ -16,6 +16,17 @@ type host struct {
 	//
 	cmd *exec.Cmd
 	//
+	scriptDir string
+	//
+	sampler sampler
+	//
+	cgroup *cgroup
+	// gate holds the shell until the resource limits are applied
+	gate *os.File
+	// landlock restricts the filesystem access of the command
+	landlock *landlock
+	// seccomp filters the syscalls of the command
+	seccomp *seccompFilter
 
 	//
 	stdin  io.Reader
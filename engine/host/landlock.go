@@ -0,0 +1,52 @@
+package host
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// ErrLandlockUnavailable is returned when a filesystem policy is requested but
+// the kernel does not support Landlock.
+var ErrLandlockUnavailable = errors.New("landlock is unavailable")
+
+// fsPolicyPaths resolves the paths of the policy against the workdir and adds
+// the implicit ones: / read-only by default, the workdir and /dev/null writable
+// and the staged script readable.
+func (h *host) fsPolicyPaths() (readOnly, readWrite, deny []string) {
+	policy := h.cfg.FSPolicy
+
+	resolve := func(paths []string) []string {
+		resolved := make([]string, 0, len(paths))
+		for _, path := range paths {
+			if !filepath.IsAbs(path) && h.cfg.WorkDir != "" {
+				path = filepath.Join(h.cfg.WorkDir, path)
+			}
+
+			if abs, err := filepath.Abs(path); err == nil {
+				resolved = append(resolved, abs)
+			}
+		}
+		return resolved
+	}
+
+	readOnly = resolve(policy.ReadOnly)
+	if len(readOnly) == 0 {
+		readOnly = []string{"/"}
+	}
+	if h.scriptDir != "" {
+		readOnly = append(readOnly, h.scriptDir)
+	}
+
+	readWrite = append(resolve(policy.ReadWrite), "/dev/null")
+	if h.cfg.WorkDir != "" {
+		readWrite = append(readWrite, resolve([]string{h.cfg.WorkDir})...)
+	}
+
+	return readOnly, readWrite, resolve(policy.Deny)
+}
+
+// isWithin reports whether path is dir or below it.
+func isWithin(path, dir string) bool {
+	return path == dir || dir == "/" || strings.HasPrefix(path, dir+"/")
+}
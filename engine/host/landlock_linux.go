@@ -0,0 +1,146 @@
+package host
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// landlockRead is the access of read-only paths
+	landlockRead = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+	// landlockFile is the access which applies to files, the rest only to directories
+	landlockFile = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE | unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+)
+
+// landlock is a ruleset enforced on the command when it starts.
+type landlock struct {
+	fd int
+}
+
+// landlockAccess returns the filesystem access handled by the Landlock ABI version.
+func landlockAccess(abi int) uint64 {
+	// ABI 1: execute, read, write and create/remove of files and directories
+	access := uint64(unix.LANDLOCK_ACCESS_FS_MAKE_SYM<<1 - 1)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	if abi >= 5 {
+		access |= unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+
+	return access
+}
+
+// newLandlock creates the Landlock ruleset of the filesystem policy.
+func (h *host) newLandlock() (*landlock, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return nil, fmt.Errorf("%w: %v", ErrLandlockUnavailable, errno)
+	}
+
+	handled := landlockAccess(int(abi))
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("failed to create landlock ruleset: %v", errno)
+	}
+
+	l := &landlock{fd: int(fd)}
+
+	readOnly, readWrite, deny := h.fsPolicyPaths()
+	for _, path := range readOnly {
+		if err := l.allow(path, landlockRead&handled, deny); err != nil {
+			l.close()
+			return nil, err
+		}
+	}
+	for _, path := range readWrite {
+		if err := l.allow(path, handled, deny); err != nil {
+			l.close()
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// allow grants the access below path. As Landlock cannot take access away
+// below a rule, a path containing denied paths is split into its entries.
+// Missing paths are ignored.
+func (l *landlock) allow(path string, access uint64, deny []string) error {
+	expand := false
+	for _, denied := range deny {
+		if isWithin(path, denied) {
+			return nil
+		}
+		if isWithin(denied, path) {
+			expand = true
+		}
+	}
+
+	if expand {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil
+		}
+
+		for _, entry := range entries {
+			// symlinks are resolved by the kernel and governed by the rules of their target
+			if entry.Type()&os.ModeSymlink != 0 {
+				continue
+			}
+
+			if err := l.allow(filepath.Join(path, entry.Name()), access, deny); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil
+	}
+	defer unix.Close(fd)
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return err
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFile
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(l.fd), unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to add landlock rule for %s: %v", path, errno)
+	}
+
+	return nil
+}
+
+// restrict enforces the ruleset on the calling thread.
+func (l *landlock) restrict() error {
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(l.fd), 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce landlock ruleset: %v", errno)
+	}
+
+	return nil
+}
+
+// close releases the ruleset.
+func (l *landlock) close() {
+	if l.fd >= 0 {
+		unix.Close(l.fd)
+		l.fd = -1
+	}
+}
@@ -0,0 +1,159 @@
+package host
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/engine"
+)
+
+func TestLandlock_FSPolicy(t *testing.T) {
+	workdir := t.TempDir()
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret")
+	if err := os.WriteFile(filepath.Join(outside, "public"), []byte("public\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(secret, []byte("secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	eng, err := New(&Config{
+		Command: "echo ok > inside && echo inside; " +
+			"echo ok 2>/dev/null > " + outside + "/written && echo outside; " +
+			"cat " + outside + "/public; " +
+			"cat " + secret + " 2>/dev/null; true",
+		WorkDir: workdir,
+		FSPolicy: &config.FSPolicy{
+			Deny: []string{secret},
+		},
+	})
+	if errors.Is(err, ErrLandlockUnavailable) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	buf := &strings.Builder{}
+	eng.SetStdout(buf)
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := eng.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if got := strings.Fields(buf.String()); len(got) != 2 || got[0] != "inside" || got[1] != "public" {
+		t.Errorf("expected writes only inside the workdir and the secret denied, got %q", buf.String())
+	}
+	if _, err := os.Stat(filepath.Join(outside, "written")); err == nil {
+		t.Error("expected no file written outside the workdir")
+	}
+}
+
+func TestLandlock_Fallback(t *testing.T) {
+	eng, err := New(&Config{
+		Command: "true",
+		FSPolicy: &config.FSPolicy{
+			IsFallbackEnabled: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error with fallback enabled, got %v", err)
+	}
+
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := eng.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+}
+
+func TestLandlock_Exec(t *testing.T) {
+	workdir := t.TempDir()
+	outside := t.TempDir()
+
+	eng, err := New(&Config{
+		Command:  "true",
+		WorkDir:  workdir,
+		FSPolicy: &config.FSPolicy{},
+	})
+	if errors.Is(err, ErrLandlockUnavailable) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	child, err := eng.(engine.Execer).Exec(&engine.ExecConfig{
+		Command: "echo ok 2>/dev/null > " + outside + "/written && echo outside; echo ok > inside && echo inside",
+		WorkDir: workdir,
+	})
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+
+	buf := &strings.Builder{}
+	child.SetStdout(buf)
+	if err := child.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := child.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if strings.TrimSpace(buf.String()) != "inside" {
+		t.Errorf("expected the exec'd process to write only inside the workdir, got %q", buf.String())
+	}
+	if _, err := os.Stat(filepath.Join(outside, "written")); err == nil {
+		t.Error("expected no file written outside the workdir")
+	}
+}
+
+func TestLandlock_Session(t *testing.T) {
+	workdir := t.TempDir()
+	outside := t.TempDir()
+
+	s, err := NewSession(&Config{
+		WorkDir:  workdir,
+		FSPolicy: &config.FSPolicy{},
+	})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer s.Close()
+
+	run := func(command string) string {
+		eng, err := s.Exec(&engine.ExecConfig{Command: command, WorkDir: workdir})
+		if errors.Is(err, ErrLandlockUnavailable) {
+			t.Skip(err)
+		}
+		if err != nil {
+			t.Fatalf("Exec: %v", err)
+		}
+
+		buf := &strings.Builder{}
+		eng.SetStdout(buf)
+		if err := eng.Start(); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		if err := eng.Wait(); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+		return strings.TrimSpace(buf.String())
+	}
+
+	run("export GO_ZOOX_SESSION=kept; echo ok 2>/dev/null > " + outside + "/written && echo outside; true")
+	if got := run("echo $GO_ZOOX_SESSION"); got != "kept" {
+		t.Errorf("expected the session state to be kept, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "written")); err == nil {
+		t.Error("expected no file written outside the workdir")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package host
+
+import "fmt"
+
+// landlock is only available on linux.
+type landlock struct{}
+
+func (h *host) newLandlock() (*landlock, error) {
+	return nil, fmt.Errorf("%w: landlock is only supported on linux", ErrLandlockUnavailable)
+}
+
+func (l *landlock) restrict() error {
+	return nil
+}
+
+func (l *landlock) close() {}
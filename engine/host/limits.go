@@ -0,0 +1,60 @@
+package host
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-zoox/command/config"
+)
+
+// limitsGate makes the shell wait until the limits are applied, as there is
+// no hook between fork and exec. The gate is passed as fd 3.
+const limitsGate = "read _ <&3; exec 3<&-\n"
+
+// hasLimits reports whether resource limits are configured.
+func (c *Config) hasLimits() bool {
+	return c.Limits != config.Limits{}
+}
+
+// gateLimits holds the command at the gate until releaseLimits applies the limits.
+func (h *host) gateLimits(command string) (string, error) {
+	if !isLimitsSupported {
+		return "", fmt.Errorf("resource limits are only supported on linux")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+
+	h.cmd.ExtraFiles = append(h.cmd.ExtraFiles, r)
+	h.gate = w
+
+	if command == "" {
+		// interactive shell
+		command = "exec " + h.cfg.Shell
+	}
+
+	return limitsGate + command, nil
+}
+
+// releaseLimits applies the limits to the started shell and opens the gate.
+func (h *host) releaseLimits() error {
+	if h.gate == nil {
+		return nil
+	}
+	defer h.gate.Close()
+
+	for _, f := range h.cmd.ExtraFiles {
+		f.Close()
+	}
+
+	if err := setLimits(h.cmd.Process.Pid, h.cfg.Limits); err != nil {
+		h.cmd.Process.Kill()
+		h.cmd.Wait()
+		return fmt.Errorf("failed to apply resource limits: %v", err)
+	}
+
+	_, err := h.gate.Write([]byte("\n"))
+	return err
+}
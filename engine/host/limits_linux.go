@@ -0,0 +1,86 @@
+package host
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/errors"
+	"golang.org/x/sys/unix"
+)
+
+const isLimitsSupported = true
+
+// setLimits applies the limits to the process with prlimit.
+func setLimits(pid int, limits config.Limits) error {
+	set := func(resource int, soft, hard uint64) error {
+		return unix.Prlimit(pid, resource, &unix.Rlimit{Cur: soft, Max: hard}, nil)
+	}
+
+	if limits.CPU != 0 {
+		// the hard limit is one second above, so SIGXCPU is sent before SIGKILL
+		if err := set(unix.RLIMIT_CPU, uint64(limits.CPU), uint64(limits.CPU)+1); err != nil {
+			return fmt.Errorf("RLIMIT_CPU: %v", err)
+		}
+	}
+	if limits.AddressSpace != 0 {
+		if err := set(unix.RLIMIT_AS, uint64(limits.AddressSpace), uint64(limits.AddressSpace)); err != nil {
+			return fmt.Errorf("RLIMIT_AS: %v", err)
+		}
+	}
+	if limits.OpenFiles != 0 {
+		if err := set(unix.RLIMIT_NOFILE, uint64(limits.OpenFiles), uint64(limits.OpenFiles)); err != nil {
+			return fmt.Errorf("RLIMIT_NOFILE: %v", err)
+		}
+	}
+	if limits.Processes != 0 {
+		if err := set(unix.RLIMIT_NPROC, uint64(limits.Processes), uint64(limits.Processes)); err != nil {
+			return fmt.Errorf("RLIMIT_NPROC: %v", err)
+		}
+	}
+	if limits.FileSize != 0 {
+		if err := set(unix.RLIMIT_FSIZE, uint64(limits.FileSize), uint64(limits.FileSize)); err != nil {
+			return fmt.Errorf("RLIMIT_FSIZE: %v", err)
+		}
+	}
+	if limits.DisableCoreDump {
+		if err := set(unix.RLIMIT_CORE, 0, 0); err != nil {
+			return fmt.Errorf("RLIMIT_CORE: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// limitExceeded converts an exit caused by a limit signal into a LimitExceededError.
+// The shell either dies from the signal or reports it as 128+n for a killed child.
+func limitExceeded(state *os.ProcessState, limits config.Limits) error {
+	signal := syscall.Signal(0)
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		signal = status.Signal()
+	} else if code := state.ExitCode(); code > 128 {
+		signal = syscall.Signal(code - 128)
+	}
+
+	var limit, name string
+	switch {
+	case signal == syscall.SIGXCPU && limits.CPU != 0:
+		limit, name = "cpu", "SIGXCPU"
+	case signal == syscall.SIGXFSZ && limits.FileSize != 0:
+		limit, name = "fsize", "SIGXFSZ"
+	default:
+		return nil
+	}
+
+	return &errors.LimitExceededError{
+		ExitError: &errors.ExitError{
+			Code:    128 + int(signal),
+			Message: fmt.Sprintf("killed by %s: %s limit exceeded", name, limit),
+			Reason:  errors.ReasonSignal,
+			Signal:  name,
+		},
+		Limit:  limit,
+		Signal: name,
+	}
+}
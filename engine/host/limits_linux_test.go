@@ -0,0 +1,88 @@
+package host
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/command/config"
+	cmderrors "github.com/go-zoox/command/errors"
+)
+
+func TestLimits_Applied(t *testing.T) {
+	eng, err := New(&Config{
+		Command: "ulimit -n; ulimit -c",
+		Limits: config.Limits{
+			OpenFiles:       64,
+			DisableCoreDump: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	buf := &strings.Builder{}
+	eng.SetStdout(buf)
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := eng.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if got := strings.Fields(buf.String()); len(got) != 2 || got[0] != "64" || got[1] != "0" {
+		t.Errorf("expected limits 64 and 0, got %q", buf.String())
+	}
+}
+
+func TestLimits_CPUExceeded(t *testing.T) {
+	eng, err := New(&Config{
+		Command: "while :; do :; done",
+		Limits: config.Limits{
+			CPU: 1,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	err = eng.Wait()
+	var limitErr *cmderrors.LimitExceededError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected LimitExceededError, got %v", err)
+	}
+	if limitErr.Limit != "cpu" || limitErr.Signal != "SIGXCPU" {
+		t.Errorf("unexpected limit error: %+v", limitErr)
+	}
+}
+
+func TestLimits_FileSizeExceeded(t *testing.T) {
+	eng, err := New(&Config{
+		Command: "head -c 4096 /dev/zero > " + filepath.Join(t.TempDir(), "out"),
+		Limits: config.Limits{
+			FileSize: 1024,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	eng.SetStderr(&strings.Builder{})
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	err = eng.Wait()
+	var limitErr *cmderrors.LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "fsize" {
+		t.Fatalf("expected fsize LimitExceededError, got %v", err)
+	}
+
+	var exitErr *cmderrors.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 153 {
+		t.Errorf("expected exit code 153, got %v", exitErr)
+	}
+}
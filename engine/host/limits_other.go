@@ -0,0 +1,20 @@
+//go:build !linux
+
+package host
+
+import (
+	"errors"
+	"os"
+
+	"github.com/go-zoox/command/config"
+)
+
+const isLimitsSupported = false
+
+func setLimits(pid int, limits config.Limits) error {
+	return errors.New("resource limits are only supported on linux")
+}
+
+func limitExceeded(state *os.ProcessState, limits config.Limits) error {
+	return nil
+}
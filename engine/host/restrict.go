@@ -0,0 +1,15 @@
+package host
+
+// startCommand starts the command, restricted by the filesystem policy and
+// the seccomp filter if any.
+func (h *host) startCommand(start func() error) error {
+	if h.landlock == nil && h.seccomp == nil {
+		return start()
+	}
+
+	if h.landlock != nil {
+		defer h.landlock.close()
+	}
+
+	return startRestricted(h.cmd, h.landlock, h.seccomp, start)
+}
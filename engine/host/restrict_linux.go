@@ -0,0 +1,116 @@
+package host
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// restrictEnvKey carries the restrictions to the re-executed helper.
+const restrictEnvKey = "GO_ZOOX_COMMAND_HOST_RESTRICT"
+
+// restrictArg0 is the process name of the helper.
+const restrictArg0 = "go-zoox-command-host-restrict"
+
+// restrictExitCode is the exit code when the restrictions cannot be applied.
+const restrictExitCode = 125
+
+// restrictConfig is passed from the engine to the helper.
+type restrictConfig struct {
+	// Landlock is the fd of the Landlock ruleset in the helper, -1 without one
+	Landlock int
+	// Seccomp is the seccomp filter, empty without one
+	Seccomp []unix.SockFilter
+}
+
+// startRestricted starts the command through a re-executed helper which applies
+// the restrictions between fork and exec, as os/exec has no hook for it. The
+// parent process is never restricted itself, so a seccomp filter killing on a
+// denied syscall cannot take it down.
+func startRestricted(cmd *exec.Cmd, l *landlock, s *seccompFilter, start func() error) error {
+	cfg := &restrictConfig{Landlock: -1}
+	if s != nil {
+		cfg.Seccomp = s.filter
+	}
+	if l != nil {
+		fd, err := unix.Dup(l.fd)
+		if err != nil {
+			return fmt.Errorf("failed to pass landlock ruleset: %v", err)
+		}
+
+		ruleset := os.NewFile(uintptr(fd), "landlock")
+		defer ruleset.Close()
+
+		cfg.Landlock = 3 + len(cmd.ExtraFiles)
+		cmd.ExtraFiles = append(cmd.ExtraFiles, ruleset)
+	}
+
+	payload, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	cmd.Env = append(cmd.Env, restrictEnvKey+"="+string(payload))
+	cmd.Args = append([]string{restrictArg0, cmd.Path}, cmd.Args...)
+	cmd.Path = "/proc/self/exe"
+
+	return start()
+}
+
+func init() {
+	payload, ok := os.LookupEnv(restrictEnvKey)
+	if !ok {
+		return
+	}
+
+	// the restrictions apply to the calling thread, which execve keeps
+	runtime.LockOSThread()
+
+	cfg := &restrictConfig{}
+	if err := json.Unmarshal([]byte(payload), cfg); err != nil {
+		restrictFail(err)
+	}
+	if len(os.Args) < 3 {
+		restrictFail(fmt.Errorf("missing command"))
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		restrictFail(fmt.Errorf("failed to set no_new_privs: %v", err))
+	}
+
+	if cfg.Landlock >= 0 {
+		l := &landlock{fd: cfg.Landlock}
+		if err := l.restrict(); err != nil {
+			restrictFail(err)
+		}
+		l.close()
+	}
+
+	// installed last, as it may deny the syscalls of the other restrictions
+	if len(cfg.Seccomp) != 0 {
+		s := &seccompFilter{filter: cfg.Seccomp}
+		if err := s.install(); err != nil {
+			restrictFail(err)
+		}
+	}
+
+	env := []string{}
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, restrictEnvKey+"=") {
+			env = append(env, kv)
+		}
+	}
+
+	restrictFail(syscall.Exec(os.Args[1], os.Args[2:], env))
+}
+
+func restrictFail(err error) {
+	fmt.Fprintf(os.Stderr, "host: %v\n", err)
+	os.Exit(restrictExitCode)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package host
+
+import "os/exec"
+
+// startRestricted starts the command, restrictions are only supported on linux.
+func startRestricted(cmd *exec.Cmd, l *landlock, s *seccompFilter, start func() error) error {
+	return start()
+}
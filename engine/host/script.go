@@ -0,0 +1,47 @@
+package host
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-zoox/command/engine"
+)
+
+// stageScript writes the script into a temporary directory and runs it instead of the command.
+func (h *host) stageScript() error {
+	if h.cfg.Script == "" {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "go-zoox-command-script-")
+	if err != nil {
+		return err
+	}
+
+	// the temporary directory is private, but the interpreter may run as Config.User
+	if err := os.Chmod(dir, 0755); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	path := filepath.Join(dir, engine.ScriptFileName)
+	if err := os.WriteFile(path, []byte(h.cfg.Script), 0755); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	if err := os.Chmod(path, 0755); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	h.scriptDir = dir
+	h.cfg.Command = engine.ScriptCommand(h.cfg.Interpreter, path)
+	return nil
+}
+
+// removeScript removes the staged script.
+func (h *host) removeScript() {
+	if h.scriptDir != "" {
+		os.RemoveAll(h.scriptDir)
+	}
+}
@@ -0,0 +1,31 @@
+package host
+
+import (
+	"github.com/go-zoox/command/seccomp"
+	"golang.org/x/sys/unix"
+)
+
+// seccompFilter is a seccomp-bpf filter installed on the command when it starts.
+type seccompFilter struct {
+	filter []unix.SockFilter
+}
+
+// newSeccomp compiles the seccomp profile of the config.
+func (h *host) newSeccomp() (*seccompFilter, error) {
+	profile, err := seccomp.Load(h.cfg.Seccomp)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := seccomp.Filter(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seccompFilter{filter: filter}, nil
+}
+
+// install installs the filter on the calling thread.
+func (s *seccompFilter) install() error {
+	return seccomp.Install(s.filter)
+}
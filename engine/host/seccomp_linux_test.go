@@ -0,0 +1,120 @@
+package host
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/errors"
+	"github.com/go-zoox/command/seccomp"
+)
+
+func TestSeccomp_Profile(t *testing.T) {
+	eng, err := New(&Config{
+		Command: "mkdir dir 2>/dev/null || echo denied",
+		WorkDir: t.TempDir(),
+		Seccomp: `{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [{"names": ["mkdir", "mkdirat"], "action": "SCMP_ACT_ERRNO"}]}`,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	buf := &strings.Builder{}
+	eng.SetStdout(buf)
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := eng.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if strings.TrimSpace(buf.String()) != "denied" {
+		t.Errorf("expected mkdir denied, got %q", buf.String())
+	}
+}
+
+func TestSeccomp_NoNetwork(t *testing.T) {
+	eng, err := New(&Config{
+		Command: `perl -MSocket -e 'socket(my $s, PF_UNIX, SOCK_STREAM, 0) and print "unix\n"; socket($s, PF_INET, SOCK_STREAM, 0) and print "inet\n"'`,
+		Seccomp: seccomp.NoNetwork,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	buf := &strings.Builder{}
+	eng.SetStdout(buf)
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := eng.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if strings.TrimSpace(buf.String()) != "unix" {
+		t.Errorf("expected only unix sockets, got %q", buf.String())
+	}
+}
+
+func TestSeccomp_KillProcessSparesParent(t *testing.T) {
+	eng, err := New(&Config{
+		Command: "mkdir dir",
+		WorkDir: t.TempDir(),
+		Seccomp: `{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [{"names": ["mkdir", "mkdirat"], "action": "SCMP_ACT_KILL_PROCESS"}]}`,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	eng.SetStdout(&strings.Builder{})
+	eng.SetStderr(&strings.Builder{})
+	if err := eng.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// the test process survives the command being killed on the denied syscall
+	err = eng.Wait()
+	var exitErr *errors.ExitError
+	if !stderrors.As(err, &exitErr) || (exitErr.Signal != "SIGSYS" && errors.SignalName(errors.ExitSignal(exitErr.Code)) != "SIGSYS") {
+		t.Fatalf("expected the command to be killed by SIGSYS, got %v", err)
+	}
+
+	if err := os.Mkdir(filepath.Join(t.TempDir(), "parent"), 0755); err != nil {
+		t.Errorf("expected the parent to be unrestricted, got %v", err)
+	}
+}
+
+func TestSeccomp_Exec(t *testing.T) {
+	eng, err := New(&Config{
+		Command: "true",
+		WorkDir: t.TempDir(),
+		Seccomp: `{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [{"names": ["mkdir", "mkdirat"], "action": "SCMP_ACT_ERRNO"}]}`,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	child, err := eng.(engine.Execer).Exec(&engine.ExecConfig{
+		Command: "mkdir dir 2>/dev/null || echo denied",
+		WorkDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+
+	buf := &strings.Builder{}
+	child.SetStdout(buf)
+	if err := child.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := child.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if strings.TrimSpace(buf.String()) != "denied" {
+		t.Errorf("expected mkdir denied in the exec'd process, got %q", buf.String())
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package host
+
+import "fmt"
+
+// seccompFilter is only available on linux.
+type seccompFilter struct{}
+
+func (h *host) newSeccomp() (*seccompFilter, error) {
+	return nil, fmt.Errorf("seccomp is only supported on linux")
+}
+
+func (s *seccompFilter) install() error {
+	return nil
+}
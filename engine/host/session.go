@@ -0,0 +1,49 @@
+package host
+
+import (
+	"os"
+
+	"github.com/go-zoox/command/engine"
+)
+
+type session struct {
+	cfg *Config
+	//
+	stateDir string
+}
+
+// NewSession creates a new host session, which runs every command in a fresh
+// shell that restores the working directory and environment left by the previous one.
+func NewSession(cfg *Config) (engine.Session, error) {
+	if cfg.Shell == "" {
+		cfg.Shell = "/bin/sh"
+	}
+
+	stateDir, err := os.MkdirTemp("", "go-zoox-command-session-")
+	if err != nil {
+		return nil, err
+	}
+
+	return &session{
+		cfg:      cfg,
+		stateDir: stateDir,
+	}, nil
+}
+
+// Exec creates a command running in the session.
+func (s *session) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	child := s.cfg.exec(cfg, engine.WrapSessionCommand(s.stateDir, cfg.Command))
+	// the session state is written by every command
+	if child.FSPolicy != nil {
+		policy := *child.FSPolicy
+		policy.ReadWrite = append(append([]string{}, policy.ReadWrite...), s.stateDir)
+		child.FSPolicy = &policy
+	}
+
+	return New(child)
+}
+
+// Close removes the session state.
+func (s *session) Close() error {
+	return os.RemoveAll(s.stateDir)
+}
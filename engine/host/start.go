@@ -19,7 +19,16 @@ func (h *host) Start() error {
 		return nil
 	}
 
-	return h.cmd.Start()
+	if err := h.startCommand(h.cmd.Start); err != nil {
+		return err
+	}
+
+	if err := h.releaseLimits(); err != nil {
+		return err
+	}
+
+	h.startSampling()
+	return nil
 }
 
 func applyStdin(cmd *exec.Cmd, stdin io.Reader) error {
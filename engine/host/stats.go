@@ -0,0 +1,103 @@
+package host
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/command/result"
+)
+
+// statsInterval is the interval of sampling the resource usage while running.
+const statsInterval = 500 * time.Millisecond
+
+// sampler tracks the resource usage of the process tree while it runs.
+type sampler struct {
+	sync.Mutex
+	//
+	last result.Stats
+	done chan struct{}
+	// final is the summary once the process is waited for
+	final *result.Stats
+}
+
+// Stats returns the resource usage of the command, read from its cgroup if limits
+// are enforced, otherwise sampled from /proc while running and from rusage once exited.
+func (h *host) Stats() (*result.Stats, error) {
+	if h.cmd == nil || h.cmd.Process == nil {
+		return nil, errors.New("command is not started")
+	}
+
+	h.sampler.Lock()
+	final := h.sampler.final
+	h.sampler.Unlock()
+	if final != nil {
+		stats := *final
+		return &stats, nil
+	}
+
+	return h.sample()
+}
+
+// sample reads the live resource usage and tracks the peak memory.
+func (h *host) sample() (stats *result.Stats, err error) {
+	if h.cgroup != nil {
+		stats, err = h.cgroup.stats()
+	} else {
+		stats, err = liveStats(h.cmd.Process.Pid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	h.sampler.Lock()
+	defer h.sampler.Unlock()
+
+	stats.PeakMemory = max(stats.PeakMemory, stats.Memory, h.sampler.last.PeakMemory)
+	h.sampler.last = *stats
+	return stats, nil
+}
+
+// startSampling samples the resource usage periodically until the command is waited for.
+func (h *host) startSampling() {
+	h.sampler.done = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(statsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-h.sampler.done:
+				return
+			case <-ticker.C:
+				h.sample()
+			}
+		}
+	}()
+}
+
+// stopSampling stops sampling and records the final summary from the cgroup or rusage.
+func (h *host) stopSampling() {
+	if h.sampler.done == nil {
+		return
+	}
+	close(h.sampler.done)
+
+	if h.cmd.ProcessState == nil {
+		return
+	}
+
+	final := usageStats(h.cmd.ProcessState)
+	if h.cgroup != nil {
+		if stats, err := h.cgroup.stats(); err == nil {
+			final = stats
+		}
+	}
+
+	h.sampler.Lock()
+	defer h.sampler.Unlock()
+
+	final.PeakMemory = max(final.PeakMemory, final.Memory, h.sampler.last.PeakMemory)
+	h.sampler.final = final
+}
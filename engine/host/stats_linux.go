@@ -0,0 +1,145 @@
+package host
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-zoox/command/result"
+)
+
+// clockTicks is USER_HZ, the unit of the cpu times in /proc/<pid>/stat, which is 100 on linux.
+const clockTicks = 100
+
+// procStat is the subset of /proc/<pid>/stat used for stats.
+type procStat struct {
+	ppid int
+	// cpu is utime + stime + cutime + cstime, unit: clock ticks
+	userTicks   int64
+	systemTicks int64
+	// rss is the resident set size, unit: pages
+	rss int64
+}
+
+// liveStats sums the resource usage of the process tree rooted at pid from /proc.
+func liveStats(pid int) (*result.Stats, error) {
+	procs, err := readProcStats()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := procs[pid]; !ok {
+		return nil, os.ErrProcessDone
+	}
+
+	children := map[int][]int{}
+	for p, stat := range procs {
+		children[stat.ppid] = append(children[stat.ppid], p)
+	}
+
+	stats := &result.Stats{}
+	pageSize := int64(os.Getpagesize())
+	queue := []int{pid}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = append(queue[1:], children[p]...)
+
+		stat := procs[p]
+		stats.UserTime += time.Duration(stat.userTicks) * time.Second / clockTicks
+		stats.SystemTime += time.Duration(stat.systemTicks) * time.Second / clockTicks
+		stats.Memory += stat.rss * pageSize
+
+		read, write := readProcIO(p)
+		stats.BlockRead += read
+		stats.BlockWrite += write
+	}
+	stats.CPUTime = stats.UserTime + stats.SystemTime
+	stats.PeakMemory = stats.Memory
+
+	return stats, nil
+}
+
+func readProcStats() (map[int]*procStat, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	procs := map[int]*procStat{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		stat, err := readProcStat(pid)
+		if err != nil {
+			// the process exited meanwhile
+			continue
+		}
+
+		procs[pid] = stat
+	}
+
+	return procs, nil
+}
+
+func readProcStat(pid int) (*procStat, error) {
+	b, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return nil, err
+	}
+
+	// the command name may contain spaces and parentheses, fields start after the last one.
+	// fields[0] is field 3 (state) in proc(5).
+	i := bytes.LastIndexByte(b, ')')
+	if i < 0 {
+		return nil, os.ErrInvalid
+	}
+	fields := strings.Fields(string(b[i+1:]))
+	if len(fields) < 22 {
+		return nil, os.ErrInvalid
+	}
+
+	field := func(n int) int64 {
+		v, _ := strconv.ParseInt(fields[n-3], 10, 64)
+		return v
+	}
+
+	return &procStat{
+		ppid:        int(field(4)),
+		userTicks:   field(14) + field(16),
+		systemTicks: field(15) + field(17),
+		rss:         field(24),
+	}, nil
+}
+
+// readProcIO returns the bytes read from and written to storage, zero if not permitted.
+func readProcIO(pid int) (read, write int64) {
+	f, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), "io"))
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ": ")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "read_bytes":
+			read, _ = strconv.ParseInt(value, 10, 64)
+		case "write_bytes":
+			write, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+
+	return read, write
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package host
+
+import (
+	"errors"
+
+	"github.com/go-zoox/command/result"
+)
+
+// liveStats is only available on linux, stats are reported once the command exits.
+func liveStats(pid int) (*result.Stats, error) {
+	return nil, errors.New("live stats are only supported on linux")
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package host
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+
+	"github.com/go-zoox/command/result"
+)
+
+// usageStats converts the rusage of the exited process, which includes its waited-for descendants.
+func usageStats(state *os.ProcessState) *result.Stats {
+	stats := &result.Stats{
+		UserTime:   state.UserTime(),
+		SystemTime: state.SystemTime(),
+	}
+	stats.CPUTime = stats.UserTime + stats.SystemTime
+
+	if rusage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		// ru_maxrss is in kilobytes on linux and bytes on darwin
+		stats.PeakMemory = int64(rusage.Maxrss)
+		if runtime.GOOS != "darwin" {
+			stats.PeakMemory *= 1024
+		}
+
+		// block operations are counted in 512-byte units
+		stats.BlockRead = int64(rusage.Inblock) * 512
+		stats.BlockWrite = int64(rusage.Oublock) * 512
+	}
+
+	return stats
+}
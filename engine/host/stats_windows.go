@@ -0,0 +1,18 @@
+package host
+
+import (
+	"os"
+
+	"github.com/go-zoox/command/result"
+)
+
+// usageStats converts the CPU times of the exited process.
+func usageStats(state *os.ProcessState) *result.Stats {
+	stats := &result.Stats{
+		UserTime:   state.UserTime(),
+		SystemTime: state.SystemTime(),
+	}
+	stats.CPUTime = stats.UserTime + stats.SystemTime
+
+	return stats
+}
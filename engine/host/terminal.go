@@ -11,10 +11,34 @@ import (
 
 // Name is the name of the engine.
 func (h *host) Terminal() (terminal.Terminal, error) {
-	terminal, err := pty.Start(h.cmd)
+	// same as pty.StartWithAttrs, but the pty is opened before the command
+	// start is restricted by the filesystem policy
+	terminal, tty, err := pty.Open()
 	if err != nil {
 		return nil, err
 	}
+	defer tty.Close()
+
+	if h.cmd.Stdout == nil {
+		h.cmd.Stdout = tty
+	}
+	if h.cmd.Stderr == nil {
+		h.cmd.Stderr = tty
+	}
+	if h.cmd.Stdin == nil {
+		h.cmd.Stdin = tty
+	}
+	h.cmd.SysProcAttr = ptyAttrs(h.cmd)
+
+	if err := h.startCommand(h.cmd.Start); err != nil {
+		terminal.Close()
+		return nil, err
+	}
+
+	if err := h.releaseLimits(); err != nil {
+		terminal.Close()
+		return nil, err
+	}
 
 	return &Terminal{
 		File:     terminal,
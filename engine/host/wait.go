@@ -1,14 +1,27 @@
 package host
 
 import (
+	"fmt"
 	"os/exec"
+	"syscall"
 
 	"github.com/go-zoox/command/errors"
 )
 
 // Wait waits for the command to finish.
 func (h *host) Wait() error {
-	if err := h.cmd.Wait(); err != nil {
+	defer h.removeScript()
+
+	err := h.cmd.Wait()
+	h.stopSampling()
+
+	isOOMKilled := false
+	if h.cgroup != nil {
+		isOOMKilled = h.cgroup.isOOMKilled()
+		h.cgroup.remove()
+	}
+
+	if err != nil {
 		v, ok := err.(*exec.ExitError)
 		if !ok {
 			return &errors.ExitError{
@@ -17,11 +30,36 @@ func (h *host) Wait() error {
 			}
 		}
 
-		return &errors.ExitError{
-			Code:    v.ExitCode(),
-			Message: v.Error(),
+		if err := limitExceeded(v.ProcessState, h.cfg.Limits); err != nil && h.cfg.hasLimits() {
+			return err
+		}
+
+		if isOOMKilled {
+			return &errors.ExitError{
+				Code:      v.ExitCode(),
+				Message:   fmt.Sprintf("%s: killed by the OOM killer (memory limit: %dMB)", v.Error(), h.cfg.Memory),
+				OOMKilled: true,
+				Reason:    errors.ReasonOOMKilled,
+			}
 		}
+
+		return exitError(v)
 	}
 
 	return nil
 }
+
+// exitError converts the exit of the process, with the Signal reason if a signal killed it.
+func exitError(v *exec.ExitError) *errors.ExitError {
+	exitErr := &errors.ExitError{
+		Code:    v.ExitCode(),
+		Message: v.Error(),
+	}
+
+	if status, ok := v.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		exitErr.Reason = errors.ReasonSignal
+		exitErr.Signal = errors.SignalName(int(status.Signal()))
+	}
+
+	return exitErr
+}
@@ -0,0 +1,59 @@
+package dockerapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/api/types"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/go-zoox/command/build"
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/pull"
+)
+
+// BuildImage builds the image of b for the platform and returns its tag, unless
+// the image of the same content was built before and the pull policy does not
+// pull the base image again. The base images are pulled with the credentials
+// of auths, and the build output is written to log.
+func BuildImage(c *dockerClient.Client, b *config.Build, platform, pullPolicy string, auths []config.RegistryAuth, prefix string, log io.Writer) (string, error) {
+	prepared, err := build.Prepare(b, platform)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", prefix, err)
+	}
+
+	// the cache key does not cover the base image, which Always pulls again
+	if pullPolicy != config.PullAlways {
+		if _, _, err := c.ImageInspectWithRaw(context.Background(), prepared.Tag); err == nil {
+			logf(log, prefix, "use cached image %s", prepared.Tag)
+			return prepared.Tag, nil
+		}
+	}
+
+	logf(log, prefix, "build image %s ...", prepared.Tag)
+	response, err := c.ImageBuild(context.Background(), bytes.NewReader(prepared.Tar), types.ImageBuildOptions{
+		Tags:        []string{prepared.Tag},
+		Dockerfile:  prepared.Dockerfile,
+		BuildArgs:   build.Args(b.Args),
+		Target:      b.Target,
+		Platform:    platform,
+		PullParent:  pullPolicy == config.PullAlways,
+		Remove:      true,
+		ForceRemove: true,
+		AuthConfigs: pull.AuthConfigs(auths),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: build image: %w", prefix, err)
+	}
+	defer response.Body.Close()
+
+	// the stream reports the errors of the build
+	if err := jsonmessage.DisplayJSONMessagesToStream(response.Body, streams.NewOut(log), nil); err != nil {
+		return "", fmt.Errorf("%s: build image: %w", prefix, err)
+	}
+
+	return prepared.Tag, nil
+}
@@ -0,0 +1,54 @@
+package dockerapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/go-zoox/command/archive"
+)
+
+// CopyTo extracts content into the directory dst of the container.
+func (p *Process) CopyTo(ctx context.Context, dst string, content io.Reader) error {
+	return CopyTo(ctx, p.client, p.containerID, p.prefix, dst, content)
+}
+
+// CopyFrom archives the file or directory src of the container.
+func (p *Process) CopyFrom(ctx context.Context, src string) (io.ReadCloser, error) {
+	return CopyFrom(ctx, p.client, p.containerID, p.prefix, src)
+}
+
+// CopyTo extracts content into the directory dst of the container, creating it if missing.
+func CopyTo(ctx context.Context, c *client.Client, containerID, prefix, dst string, content io.Reader) error {
+	// the docker api only extracts into existing directories,
+	// so missing ones are created by prefixing the entries.
+	if _, err := c.ContainerStatPath(ctx, containerID, dst); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return fmt.Errorf("%s: stat %s: %w", prefix, dst, err)
+		}
+
+		rebased := archive.Rebase(content, dst)
+		defer rebased.Close()
+
+		content, dst = rebased, "/"
+	}
+
+	if err := c.CopyToContainer(ctx, containerID, dst, content, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("%s: copy to container: %w", prefix, err)
+	}
+
+	return nil
+}
+
+// CopyFrom archives the file or directory src of the container.
+func CopyFrom(ctx context.Context, c *client.Client, containerID, prefix, src string) (io.ReadCloser, error) {
+	reader, _, err := c.CopyFromContainer(ctx, containerID, src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: copy from container: %w", prefix, err)
+	}
+
+	return reader, nil
+}
@@ -0,0 +1,62 @@
+package dockerapi
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/go-zoox/command/archive"
+	"github.com/go-zoox/command/result"
+)
+
+// Diff returns the changes of the container layer since the container was
+// created. Bind mounts, e.g. WorkDir, are not part of the layer.
+func Diff(ctx context.Context, c *client.Client, containerID string) ([]result.Change, error) {
+	changes, err := c.ContainerDiff(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := []result.Change{}
+	for _, change := range changes {
+		if change.Kind == container.ChangeDelete {
+			diff = append(diff, result.Change{Path: change.Path, Kind: result.ChangeDeleted})
+			continue
+		}
+
+		stat, err := c.ContainerStatPath(ctx, containerID, change.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		kind := result.ChangeAdded
+		if change.Kind == container.ChangeModify {
+			// the parent directories of changed files are reported as modified
+			if stat.Mode.IsDir() {
+				continue
+			}
+			kind = result.ChangeModified
+		}
+
+		change := result.Change{Path: change.Path, Kind: kind, IsDir: stat.Mode.IsDir(), Size: stat.Size}
+		if stat.Mode.IsRegular() {
+			reader, _, err := c.CopyFromContainer(ctx, containerID, change.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			change.Size, change.SHA256, err = archive.Checksum(reader)
+			reader.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if change.IsDir {
+			change.Size = 0
+		}
+
+		diff = append(diff, change)
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,7 @@
+// Package dockerapi implements the parts of the docker and podman engines
+// which only use the Docker API: exec processes and sessions, stats, mounts,
+// resource controls, oom detection, the changes of the container layer,
+// networks, services, the egress proxy and image builds. Errors are prefixed
+// with the name of the engine, which also prefixes the progress logged to its
+// log writer.
+package dockerapi
@@ -0,0 +1,129 @@
+package dockerapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/go-zoox/command/egress"
+	"github.com/go-zoox/command/result"
+)
+
+// Egress is the internal network of a container, without a route to the
+// outside, and the filtering proxy served on its gateway address.
+type Egress struct {
+	client *dockerClient.Client
+	id     string
+	allow  []string
+	prefix string
+	log    io.Writer
+	//
+	network string
+	proxy   *egress.Proxy
+	once    sync.Once
+}
+
+// NewEgress creates the egress of the run with the id, allowing only the hosts,
+// networks and ports of allow.
+func NewEgress(client *dockerClient.Client, id string, allow []string, prefix string, log io.Writer) *Egress {
+	return &Egress{
+		client: client,
+		id:     id,
+		allow:  allow,
+		prefix: prefix,
+		log:    log,
+	}
+}
+
+// Create creates the egress network, joined by the container, and serves the
+// proxy on its gateway. The proxy runs in this process, so the engine must run
+// locally.
+func (e *Egress) Create(cfg *container.Config, hostCfg *container.HostConfig, networkCfg *network.NetworkingConfig) error {
+	policy, err := egress.Parse(e.allow)
+	if err != nil {
+		return fmt.Errorf("%s: %w", e.prefix, err)
+	}
+
+	name := fmt.Sprintf("%s_egress", e.id)
+	logf(e.log, e.prefix, "create egress network %s ...", name)
+	created, err := e.client.NetworkCreate(context.Background(), name, network.CreateOptions{
+		Driver:   "bridge",
+		Internal: true,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: create egress network: %w", e.prefix, err)
+	}
+	e.network = created.ID
+
+	networkIns, err := e.client.NetworkInspect(context.Background(), created.ID, network.InspectOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: inspect egress network: %w", e.prefix, err)
+	}
+
+	gateway := ""
+	for _, ipam := range networkIns.IPAM.Config {
+		if ip := net.ParseIP(ipam.Gateway); ip != nil && ip.To4() != nil {
+			gateway = ipam.Gateway
+			break
+		}
+	}
+	if gateway == "" {
+		return fmt.Errorf("%s: egress network %s has no gateway", e.prefix, name)
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(gateway, "0"))
+	if err != nil {
+		return fmt.Errorf("%s: listen on egress network gateway (requires a local %s): %w", e.prefix, e.prefix, err)
+	}
+
+	e.proxy = egress.NewProxy(policy, func(event *result.Event) {
+		logf(e.log, e.prefix, "egress: %s", event.Message)
+	})
+	go e.proxy.Serve(listener)
+
+	cfg.Env = egress.Environment(cfg.Env, listener.Addr().String())
+	hostCfg.NetworkMode = container.NetworkMode(name)
+	networkCfg.EndpointsConfig = map[string]*network.EndpointSettings{
+		name: {NetworkID: created.ID},
+	}
+
+	logf(e.log, e.prefix, "egress proxy listening on %s", listener.Addr())
+	return nil
+}
+
+// Remove stops the proxy and removes the egress network, disconnecting the
+// container if it is kept. A nil egress is ignored.
+func (e *Egress) Remove(containerID string) {
+	if e == nil {
+		return
+	}
+
+	e.once.Do(func() {
+		if e.proxy != nil {
+			e.proxy.Close()
+		}
+
+		if e.network != "" {
+			if containerID != "" {
+				e.client.NetworkDisconnect(context.Background(), e.network, containerID, true)
+			}
+			if err := e.client.NetworkRemove(context.Background(), e.network); err != nil {
+				logf(e.log, e.prefix, "failed to remove egress network: %s", err)
+			}
+		}
+	})
+}
+
+// Events returns the connections denied by the proxy, none for a nil egress.
+func (e *Egress) Events() []result.Event {
+	if e == nil || e.proxy == nil {
+		return nil
+	}
+
+	return e.proxy.Events()
+}
@@ -0,0 +1,13 @@
+package dockerapi
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-zoox/datetime"
+)
+
+// logf writes a line of the progress of the engine named prefix to w.
+func logf(w io.Writer, prefix, format string, args ...any) {
+	fmt.Fprintf(w, "[%s][%s] %s\n", datetime.Now().Format(), prefix, fmt.Sprintf(format, args...))
+}
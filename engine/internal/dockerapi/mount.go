@@ -0,0 +1,41 @@
+package dockerapi
+
+import (
+	"github.com/docker/docker/api/types/mount"
+	"github.com/go-zoox/command/config"
+)
+
+// Mounts returns the container mounts of the configured mounts.
+func Mounts(mounts []config.Mount) []mount.Mount {
+	result := make([]mount.Mount, 0, len(mounts))
+	for _, m := range mounts {
+		switch m.Type {
+		case config.MountVolume:
+			// a volume without source is anonymous
+			result = append(result, mount.Mount{
+				Type:     mount.TypeVolume,
+				Source:   m.Source,
+				Target:   m.Target,
+				ReadOnly: m.ReadOnly,
+			})
+		case config.MountTmpfs:
+			result = append(result, mount.Mount{
+				Type:   mount.TypeTmpfs,
+				Target: m.Target,
+				TmpfsOptions: &mount.TmpfsOptions{
+					SizeBytes: m.TmpfsSize,
+					Mode:      m.TmpfsMode,
+				},
+			})
+		default:
+			result = append(result, mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   m.Source,
+				Target:   m.Target,
+				ReadOnly: m.ReadOnly,
+			})
+		}
+	}
+
+	return result
+}
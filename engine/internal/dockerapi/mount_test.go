@@ -0,0 +1,33 @@
+package dockerapi
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/go-zoox/command/config"
+)
+
+func TestMounts(t *testing.T) {
+	result := Mounts([]config.Mount{
+		{Type: config.MountBind, Source: "/data", Target: "/data", ReadOnly: true},
+		{Type: config.MountVolume, Source: "cache", Target: "/cache"},
+		{Type: config.MountVolume, Target: "/scratch"},
+		{Type: config.MountTmpfs, Target: "/tmp", TmpfsSize: 64 << 20, TmpfsMode: 01777},
+	})
+	if len(result) != 4 {
+		t.Fatalf("expected 4 mounts, got %+v", result)
+	}
+
+	if m := result[0]; m.Type != mount.TypeBind || m.Source != "/data" || !m.ReadOnly {
+		t.Errorf("unexpected bind mount: %+v", m)
+	}
+	if m := result[1]; m.Type != mount.TypeVolume || m.Source != "cache" || m.Target != "/cache" {
+		t.Errorf("unexpected named volume: %+v", m)
+	}
+	if m := result[2]; m.Type != mount.TypeVolume || m.Source != "" {
+		t.Errorf("unexpected anonymous volume: %+v", m)
+	}
+	if m := result[3]; m.Type != mount.TypeTmpfs || m.TmpfsOptions == nil || m.TmpfsOptions.SizeBytes != 64<<20 || m.TmpfsOptions.Mode != 01777 {
+		t.Errorf("unexpected tmpfs mount: %+v", m)
+	}
+}
@@ -0,0 +1,158 @@
+package dockerapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/go-zoox/command/result"
+)
+
+// DefaultHostname is the hostname of the container unless configured.
+const DefaultHostname = "go-zoox"
+
+// Network is the network configuration of a container.
+type Network struct {
+	Hostname string
+	// Ports are the published ports as [ip:][hostPort:]containerPort[/protocol]
+	Ports      []string
+	DNS        []string
+	ExtraHosts []string
+	// Network is the first network of the container, before Networks
+	Network        string
+	Networks       []string
+	NetworkAliases []string
+}
+
+// ApplyNetwork applies the hostname, published ports, DNS and extra hosts to
+// the container, and adds the endpoint of its first network. The container is
+// connected to the other networks by ConnectNetworks once it is created.
+func ApplyNetwork(c *dockerClient.Client, n *Network, prefix string, log io.Writer, cfg *container.Config, hostCfg *container.HostConfig, networkCfg *network.NetworkingConfig) error {
+	cfg.Hostname = DefaultHostname
+	if n.Hostname != "" {
+		cfg.Hostname = n.Hostname
+	}
+
+	if len(n.Ports) != 0 {
+		exposed, bindings, err := nat.ParsePortSpecs(n.Ports)
+		if err != nil {
+			return fmt.Errorf("%s: invalid ports: %w", prefix, err)
+		}
+
+		cfg.ExposedPorts = exposed
+		hostCfg.PortBindings = bindings
+	}
+
+	hostCfg.DNS = n.DNS
+	hostCfg.ExtraHosts = n.ExtraHosts
+
+	networks := n.networks()
+	if len(networks) == 0 {
+		return nil
+	}
+
+	endpoint, err := n.endpoint(c, networks[0], prefix, log)
+	if err != nil {
+		return err
+	}
+
+	networkCfg.EndpointsConfig[networks[0]] = endpoint
+	if hostCfg.NetworkMode == "" {
+		hostCfg.NetworkMode = container.NetworkMode(networks[0])
+	}
+
+	return nil
+}
+
+// ConnectNetworks connects the created container to its other networks, as
+// older API versions create containers with a single endpoint.
+func ConnectNetworks(c *dockerClient.Client, n *Network, containerID, prefix string, log io.Writer) error {
+	networks := n.networks()
+	if len(networks) < 2 {
+		return nil
+	}
+
+	for _, name := range networks[1:] {
+		endpoint, err := n.endpoint(c, name, prefix, log)
+		if err != nil {
+			return err
+		}
+
+		logf(log, prefix, "connect network %s ...", name)
+		if err := c.NetworkConnect(context.Background(), endpoint.NetworkID, containerID, endpoint); err != nil {
+			return fmt.Errorf("%s: connect network %s: %w", prefix, name, err)
+		}
+	}
+
+	return nil
+}
+
+// networks returns the networks of the container, Network first.
+func (n *Network) networks() []string {
+	if n.Network == "" {
+		return n.Networks
+	}
+
+	return append([]string{n.Network}, n.Networks...)
+}
+
+// endpoint returns the endpoint of the container on the network, with its aliases.
+func (n *Network) endpoint(c *dockerClient.Client, name, prefix string, log io.Writer) (*network.EndpointSettings, error) {
+	logf(log, prefix, "inspect network %s ...", name)
+	networkIns, err := c.NetworkInspect(context.Background(), name, network.InspectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: inspect network %s: %w", prefix, name, err)
+	}
+
+	return &network.EndpointSettings{
+		NetworkID: networkIns.ID,
+		Aliases:   n.NetworkAliases,
+	}, nil
+}
+
+// InspectPorts returns the bound host ports of the published ports of the started container.
+func InspectPorts(c *dockerClient.Client, containerID, prefix string) ([]result.Port, error) {
+	inspect, err := c.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: inspect ports: %w", prefix, err)
+	}
+	if inspect.NetworkSettings == nil {
+		return nil, nil
+	}
+
+	return bindings(inspect.NetworkSettings.Ports), nil
+}
+
+// bindings converts the port map of the container into ports, sorted by container port.
+func bindings(portMap nat.PortMap) []result.Port {
+	ports := []result.Port{}
+	for port, bindings := range portMap {
+		for _, binding := range bindings {
+			hostPort, _ := strconv.Atoi(binding.HostPort)
+			ports = append(ports, result.Port{
+				ContainerPort: port.Int(),
+				Protocol:      port.Proto(),
+				HostIP:        binding.HostIP,
+				HostPort:      hostPort,
+			})
+		}
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].ContainerPort != ports[j].ContainerPort {
+			return ports[i].ContainerPort < ports[j].ContainerPort
+		}
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].HostIP < ports[j].HostIP
+	})
+
+	return ports
+}
@@ -0,0 +1,67 @@
+package dockerapi
+
+import (
+	"io"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+)
+
+func TestApplyNetwork(t *testing.T) {
+	n := &Network{
+		Ports:      []string{"8080:80", "127.0.0.1::5432", "53/udp"},
+		DNS:        []string{"1.1.1.1"},
+		ExtraHosts: []string{"db:10.0.0.2"},
+	}
+
+	cfg := &container.Config{}
+	hostCfg := &container.HostConfig{}
+	networkCfg := &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{}}
+	if err := ApplyNetwork(nil, n, "docker", io.Discard, cfg, hostCfg, networkCfg); err != nil {
+		t.Fatalf("ApplyNetwork: %v", err)
+	}
+
+	if cfg.Hostname != DefaultHostname {
+		t.Errorf("expected default hostname, got %s", cfg.Hostname)
+	}
+	if len(cfg.ExposedPorts) != 3 {
+		t.Errorf("expected 3 exposed ports, got %v", cfg.ExposedPorts)
+	}
+	if b := hostCfg.PortBindings["80/tcp"]; len(b) != 1 || b[0].HostPort != "8080" {
+		t.Errorf("unexpected binding of 80/tcp: %v", b)
+	}
+	if b := hostCfg.PortBindings["5432/tcp"]; len(b) != 1 || b[0].HostIP != "127.0.0.1" || b[0].HostPort != "" {
+		t.Errorf("unexpected binding of 5432/tcp: %v", b)
+	}
+	if len(hostCfg.DNS) != 1 || len(hostCfg.ExtraHosts) != 1 {
+		t.Errorf("unexpected DNS %v or extra hosts %v", hostCfg.DNS, hostCfg.ExtraHosts)
+	}
+	if hostCfg.NetworkMode != "" || len(networkCfg.EndpointsConfig) != 0 {
+		t.Errorf("expected the default network, got %s", hostCfg.NetworkMode)
+	}
+
+	n.Ports = []string{"http"}
+	if err := ApplyNetwork(nil, n, "docker", io.Discard, cfg, hostCfg, networkCfg); err == nil {
+		t.Error("expected error for invalid port")
+	}
+}
+
+func TestBindings(t *testing.T) {
+	ports := bindings(nat.PortMap{
+		"80/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "32768"}, {HostIP: "::", HostPort: "32768"}},
+		"53/udp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "5353"}},
+		"22/tcp": nil,
+	})
+
+	if len(ports) != 3 {
+		t.Fatalf("expected 3 ports, got %+v", ports)
+	}
+	if p := ports[0]; p.ContainerPort != 53 || p.Protocol != "udp" || p.HostPort != 5353 {
+		t.Errorf("unexpected port: %+v", p)
+	}
+	if p := ports[1]; p.ContainerPort != 80 || p.Protocol != "tcp" || p.HostIP != "0.0.0.0" || p.HostPort != 32768 {
+		t.Errorf("unexpected port: %+v", p)
+	}
+}
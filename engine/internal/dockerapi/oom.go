@@ -0,0 +1,73 @@
+package dockerapi
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// OOMWatcher watches the oom event of a container, since an auto removed
+// container cannot be inspected for State.OOMKilled after it exits.
+type OOMWatcher struct {
+	// killed is closed on the oom event of the container
+	killed chan struct{}
+	stop   context.CancelFunc
+}
+
+// WatchOOM starts watching the oom event of the container.
+func WatchOOM(c *client.Client, containerID string) *OOMWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &OOMWatcher{
+		killed: make(chan struct{}),
+		stop:   cancel,
+	}
+
+	messages, errs := c.Events(ctx, events.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("container", containerID),
+			filters.Arg("event", string(events.ActionOOM)),
+		),
+	})
+
+	go func() {
+		select {
+		case <-messages:
+			close(w.killed)
+		case <-errs:
+		case <-ctx.Done():
+		}
+	}()
+
+	return w
+}
+
+// Stop stops watching, a nil watcher is ignored.
+func (w *OOMWatcher) Stop() {
+	if w != nil {
+		w.stop()
+	}
+}
+
+// IsOOMKilled reports whether the OOM killer killed a process of the container,
+// from the state of a kept container or else from the oom event of the watcher.
+func IsOOMKilled(c *client.Client, containerID string, isKept bool, w *OOMWatcher) bool {
+	if isKept {
+		if inspect, err := c.ContainerInspect(context.Background(), containerID); err == nil && inspect.State != nil {
+			return inspect.State.OOMKilled
+		}
+	}
+
+	if w == nil {
+		return false
+	}
+
+	select {
+	case <-w.killed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,323 @@
+package dockerapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/errors"
+	"github.com/go-zoox/command/terminal"
+	"github.com/go-zoox/uuid"
+)
+
+// processIDEnvKey marks the processes of an exec in their environment, so
+// Cancel can find and signal them from another exec.
+const processIDEnvKey = "GO_ZOOX_COMMAND_EXEC_ID"
+
+// cancelGracePeriod is how long a cancelled process has to exit after SIGTERM
+// before it is killed.
+const cancelGracePeriod = 10 * time.Second
+
+// signalCommand sends the signal $1 to every process whose environment holds $2.
+const signalCommand = `for __go_zoox_environ in /proc/[0-9]*/environ; do ` +
+	`tr '\0' '\n' 2>/dev/null < "$__go_zoox_environ" | grep -qx "$2" || continue; ` +
+	`__go_zoox_pid=${__go_zoox_environ#/proc/}; kill -"$1" "${__go_zoox_pid%/environ}" 2>/dev/null; ` +
+	`done; true`
+
+// Process is a process executed inside an existing container with the exec API.
+type Process struct {
+	cfg *engine.ExecConfig
+	//
+	client      *dockerClient.Client
+	containerID string
+	prefix      string
+	//
+	id     string
+	execID string
+	conn   net.Conn
+	done   chan error
+	//
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewProcess creates a process executing the command of cfg in the container,
+// prefixing its errors with prefix.
+func NewProcess(client *dockerClient.Client, containerID, prefix string, cfg *engine.ExecConfig) *Process {
+	if cfg.Shell == "" {
+		cfg.Shell = "/bin/sh"
+	}
+
+	return &Process{
+		cfg: cfg,
+		//
+		client:      client,
+		containerID: containerID,
+		prefix:      prefix,
+		//
+		id: uuid.V4(),
+		//
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+}
+
+func (p *Process) create(tty bool) error {
+	if p.execID != "" {
+		return fmt.Errorf("%s: process already created", p.prefix)
+	}
+
+	env := []string{fmt.Sprintf("%s=%s", processIDEnvKey, p.id)}
+	for k, v := range p.cfg.Environment {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	resp, err := p.client.ContainerExecCreate(context.Background(), p.containerID, container.ExecOptions{
+		User:         p.cfg.User,
+		WorkingDir:   p.cfg.WorkDir,
+		Env:          env,
+		Tty:          tty,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          []string{p.cfg.Shell, "-c", p.cfg.Command},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: create exec: %w", p.prefix, err)
+	}
+
+	p.execID = resp.ID
+	return nil
+}
+
+// Start starts the process.
+func (p *Process) Start() error {
+	if err := p.create(false); err != nil {
+		return err
+	}
+
+	stream, err := p.client.ContainerExecAttach(context.Background(), p.execID, container.ExecAttachOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: attach exec: %w", p.prefix, err)
+	}
+	p.conn = stream.Conn
+
+	if p.stdin != nil {
+		go func() {
+			io.Copy(stream.Conn, p.stdin)
+			stream.CloseWrite()
+		}()
+	}
+
+	stdout := p.stdout
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	stderr := p.stderr
+	if stderr == nil {
+		stderr = stdout
+	}
+
+	p.done = make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(stdout, stderr, stream.Reader)
+		p.done <- err
+	}()
+
+	return nil
+}
+
+// Wait waits for the process to exit.
+func (p *Process) Wait() error {
+	if p.done == nil {
+		return fmt.Errorf("%s: process is not started", p.prefix)
+	}
+
+	if err := <-p.done; err != nil && err != io.EOF {
+		return fmt.Errorf("%s: read exec output: %w", p.prefix, err)
+	}
+
+	return waitExec(context.Background(), p.client, p.execID, p.prefix)
+}
+
+// Cancel stops the process and its children with SIGTERM, then SIGKILL when
+// it is still running after the grace period, and closes its streams.
+func (p *Process) Cancel() error {
+	if p.conn != nil {
+		defer p.conn.Close()
+	}
+
+	if p.execID == "" {
+		return nil
+	}
+
+	if err := p.signal("TERM"); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cancelGracePeriod)
+	defer cancel()
+	if waitExec(ctx, p.client, p.execID, p.prefix); ctx.Err() == nil {
+		return nil
+	}
+
+	return p.signal("KILL")
+}
+
+// signal sends the signal to the processes of the exec with another exec in
+// the container, as the same user.
+func (p *Process) signal(signal string) error {
+	ctx := context.Background()
+
+	resp, err := p.client.ContainerExecCreate(ctx, p.containerID, container.ExecOptions{
+		User: p.cfg.User,
+		Cmd:  []string{"/bin/sh", "-c", signalCommand, "sh", signal, fmt.Sprintf("%s=%s", processIDEnvKey, p.id)},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: create signal exec: %w", p.prefix, err)
+	}
+
+	if err := p.client.ContainerExecStart(ctx, resp.ID, container.ExecStartOptions{Detach: true}); err != nil {
+		return fmt.Errorf("%s: start signal exec: %w", p.prefix, err)
+	}
+
+	return waitExec(ctx, p.client, resp.ID, p.prefix)
+}
+
+// SetStdin sets the stdin for the process.
+func (p *Process) SetStdin(stdin io.Reader) error {
+	p.stdin = stdin
+	return nil
+}
+
+// SetStdout sets the stdout for the process.
+func (p *Process) SetStdout(stdout io.Writer) error {
+	p.stdout = stdout
+	return nil
+}
+
+// SetStderr sets the stderr for the process.
+func (p *Process) SetStderr(stderr io.Writer) error {
+	p.stderr = stderr
+	return nil
+}
+
+// Terminal returns a terminal for the process.
+func (p *Process) Terminal() (terminal.Terminal, error) {
+	if err := p.create(true); err != nil {
+		return nil, err
+	}
+
+	stream, err := p.client.ContainerExecAttach(context.Background(), p.execID, container.ExecAttachOptions{
+		Tty: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: attach exec: %w", p.prefix, err)
+	}
+	p.conn = stream.Conn
+
+	return &ExecTerminal{
+		Ctx:      context.Background(),
+		Client:   p.client,
+		ExecID:   p.execID,
+		Prefix:   p.prefix,
+		Conn:     stream.Conn,
+		ReadOnly: p.cfg.ReadOnly,
+	}, nil
+}
+
+// ExecTerminal is a terminal for a process executed inside a container.
+type ExecTerminal struct {
+	Ctx  context.Context
+	Conn net.Conn
+	//
+	Client *dockerClient.Client
+	ExecID string
+	// Prefix is the prefix of the errors, the name of the engine
+	Prefix string
+	//
+	ReadOnly bool
+	//
+	sync.Mutex
+}
+
+// Close closes the terminal.
+func (t *ExecTerminal) Close() error {
+	return t.Conn.Close()
+}
+
+// Read reads from the terminal.
+func (t *ExecTerminal) Read(p []byte) (n int, err error) {
+	return t.Conn.Read(p)
+}
+
+// Write writes to the terminal.
+func (t *ExecTerminal) Write(p []byte) (n int, err error) {
+	if t.ReadOnly {
+		return 0, nil
+	}
+	t.Lock()
+	defer t.Unlock()
+
+	return t.Conn.Write(p)
+}
+
+// Resize resizes the terminal.
+func (t *ExecTerminal) Resize(rows, cols int) error {
+	return t.Client.ContainerExecResize(t.Ctx, t.ExecID, container.ResizeOptions{
+		Height: uint(rows),
+		Width:  uint(cols),
+	})
+}
+
+// ExitCode returns the exit code.
+func (t *ExecTerminal) ExitCode() int {
+	inspect, err := t.Client.ContainerExecInspect(t.Ctx, t.ExecID)
+	if err != nil {
+		return -1
+	}
+
+	return inspect.ExitCode
+}
+
+// Wait waits for the terminal to exit.
+func (t *ExecTerminal) Wait() error {
+	return waitExec(t.Ctx, t.Client, t.ExecID, t.Prefix)
+}
+
+// waitExec polls the exec instance until it is no longer running.
+func waitExec(ctx context.Context, client *dockerClient.Client, execID, prefix string) error {
+	for {
+		inspect, err := client.ContainerExecInspect(ctx, execID)
+		if err != nil {
+			return fmt.Errorf("%s: inspect exec: %w", prefix, err)
+		}
+
+		if !inspect.Running {
+			if inspect.ExitCode != 0 {
+				return &errors.ExitError{
+					Code:    inspect.ExitCode,
+					Message: fmt.Sprintf("process exited with non-zero status: %d", inspect.ExitCode),
+				}
+			}
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
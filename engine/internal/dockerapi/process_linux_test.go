@@ -0,0 +1,140 @@
+package dockerapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/go-zoox/command/engine"
+)
+
+// fakeDaemon implements the exec API by running the commands on the host.
+type fakeDaemon struct {
+	sync.Mutex
+	execs map[string]*fakeExec
+}
+
+type fakeExec struct {
+	options container.ExecOptions
+	cmd     *exec.Cmd
+	done    chan struct{}
+}
+
+func newFakeDaemon(t *testing.T) *dockerClient.Client {
+	d := &fakeDaemon{execs: map[string]*fakeExec{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1.45/containers/{id}/exec", func(w http.ResponseWriter, r *http.Request) {
+		e := &fakeExec{done: make(chan struct{})}
+		if err := json.NewDecoder(r.Body).Decode(&e.options); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		d.Lock()
+		id := string(rune('a' + len(d.execs)))
+		d.execs[id] = e
+		d.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]string{"Id": id})
+	})
+	mux.HandleFunc("POST /v1.45/exec/{id}/start", func(w http.ResponseWriter, r *http.Request) {
+		d.Lock()
+		e := d.execs[r.PathValue("id")]
+		d.Unlock()
+
+		e.cmd = exec.Command(e.options.Cmd[0], e.options.Cmd[1:]...)
+		e.cmd.Env = append(os.Environ(), e.options.Env...)
+		if err := e.cmd.Start(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		go func() {
+			e.cmd.Wait()
+			close(e.done)
+		}()
+	})
+	mux.HandleFunc("GET /v1.45/exec/{id}/json", func(w http.ResponseWriter, r *http.Request) {
+		d.Lock()
+		e := d.execs[r.PathValue("id")]
+		d.Unlock()
+
+		inspect := container.ExecInspect{Running: true}
+		select {
+		case <-e.done:
+			inspect = container.ExecInspect{ExitCode: e.cmd.ProcessState.ExitCode()}
+		default:
+		}
+		json.NewEncoder(w).Encode(inspect)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := dockerClient.NewClientWithOpts(dockerClient.WithHost("tcp://"+srv.Listener.Addr().String()), dockerClient.WithVersion("1.45"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// processesOf returns the pids of the processes of the exec marked with id.
+func processesOf(id string) []string {
+	pids := []string{}
+	files, _ := filepath.Glob("/proc/[0-9]*/environ")
+	for _, file := range files {
+		environ, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		for _, env := range strings.Split(string(environ), "\x00") {
+			if env == processIDEnvKey+"="+id {
+				pids = append(pids, filepath.Base(filepath.Dir(file)))
+			}
+		}
+	}
+	return pids
+}
+
+func TestProcess_Cancel(t *testing.T) {
+	client := newFakeDaemon(t)
+
+	p := NewProcess(client, "container", "test", &engine.ExecConfig{
+		Command: "sleep 60 & sleep 60",
+	})
+	if err := p.create(false); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := client.ContainerExecStart(context.Background(), p.execID, container.ExecStartOptions{Detach: true}); err != nil {
+		t.Fatalf("ContainerExecStart: %v", err)
+	}
+
+	for i := 0; len(processesOf(p.id)) < 3; i++ {
+		if i == 50 {
+			t.Fatalf("expected the shell and its children to run, got %v", processesOf(p.id))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if err := p.Cancel(); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	for i := 0; len(processesOf(p.id)) != 0; i++ {
+		if i == 50 {
+			t.Fatalf("expected the process and its children to be gone after Cancel, got %v", processesOf(p.id))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
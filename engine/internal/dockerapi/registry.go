@@ -0,0 +1,32 @@
+package dockerapi
+
+import (
+	"os"
+
+	"github.com/go-zoox/command/config"
+)
+
+// ImageRegistryAuth returns the credential of the image registry, nil unless
+// the registry, username and password are all set. Each of them falls back to
+// DOCKER_REGISTRY, DOCKER_REGISTRY_USERNAME and DOCKER_REGISTRY_PASSWORD.
+func ImageRegistryAuth(registry, username, password string) *config.RegistryAuth {
+	if registry == "" {
+		registry = os.Getenv("DOCKER_REGISTRY")
+	}
+	if username == "" {
+		username = os.Getenv("DOCKER_REGISTRY_USERNAME")
+	}
+	if password == "" {
+		password = os.Getenv("DOCKER_REGISTRY_PASSWORD")
+	}
+
+	if registry == "" || username == "" || password == "" {
+		return nil
+	}
+
+	return &config.RegistryAuth{
+		Registry: registry,
+		Username: username,
+		Password: password,
+	}
+}
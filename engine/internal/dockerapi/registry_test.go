@@ -0,0 +1,19 @@
+package dockerapi
+
+import "testing"
+
+func TestImageRegistryAuth(t *testing.T) {
+	t.Setenv("DOCKER_REGISTRY", "registry.example.com")
+	t.Setenv("DOCKER_REGISTRY_USERNAME", "env-user")
+	t.Setenv("DOCKER_REGISTRY_PASSWORD", "env-password")
+
+	auth := ImageRegistryAuth("", "user", "")
+	if auth == nil || auth.Registry != "registry.example.com" || auth.Username != "user" || auth.Password != "env-password" {
+		t.Errorf("expected the config to take priority over the environment, got %+v", auth)
+	}
+
+	t.Setenv("DOCKER_REGISTRY_PASSWORD", "")
+	if auth := ImageRegistryAuth("registry.example.com", "user", ""); auth != nil {
+		t.Errorf("expected no credential without password, got %+v", auth)
+	}
+}
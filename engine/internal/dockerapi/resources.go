@@ -0,0 +1,58 @@
+package dockerapi
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/go-zoox/command/config"
+)
+
+// Resources are the resource controls beyond memory, CPU and pids.
+type Resources struct {
+	// MemorySwap is the limit of memory plus swap, unit: MB, -1 means unlimited swap
+	MemorySwap int64
+	// MemoryReservation is the soft memory limit, unit: MB
+	MemoryReservation int64
+	// CPUSet are the CPUs the container can run on, e.g. 0-3 or 0,2
+	CPUSet string
+	// ShmSize is the size of /dev/shm, unit: MB
+	ShmSize int64
+	// Ulimits are the resource limits of the processes in the container
+	Ulimits []config.Ulimit
+	// BlkioWeight is the relative block I/O weight, from 10 to 1000
+	BlkioWeight uint16
+	// StorageSize is the size limit of the writable layer, unit: MB
+	StorageSize int64
+}
+
+// ApplyResources applies the resource controls to the container.
+func ApplyResources(hostCfg *container.HostConfig, r *Resources) {
+	if r.MemorySwap != 0 {
+		hostCfg.Resources.MemorySwap = -1
+		if r.MemorySwap > 0 {
+			hostCfg.Resources.MemorySwap = r.MemorySwap * 1024 * 1024
+		}
+	}
+	if r.MemoryReservation != 0 {
+		hostCfg.Resources.MemoryReservation = r.MemoryReservation * 1024 * 1024
+	}
+	if r.CPUSet != "" {
+		hostCfg.Resources.CpusetCpus = r.CPUSet
+	}
+	if r.ShmSize != 0 {
+		hostCfg.ShmSize = r.ShmSize * 1024 * 1024
+	}
+	for _, ulimit := range r.Ulimits {
+		hostCfg.Resources.Ulimits = append(hostCfg.Resources.Ulimits, &container.Ulimit{
+			Name: ulimit.Name,
+			Soft: ulimit.Soft,
+			Hard: ulimit.Hard,
+		})
+	}
+	if r.BlkioWeight != 0 {
+		hostCfg.Resources.BlkioWeight = r.BlkioWeight
+	}
+	if r.StorageSize != 0 {
+		hostCfg.StorageOpt = map[string]string{"size": fmt.Sprintf("%dM", r.StorageSize)}
+	}
+}
@@ -0,0 +1,43 @@
+package dockerapi
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/go-zoox/command/config"
+)
+
+func TestApplyResources(t *testing.T) {
+	hostCfg := &container.HostConfig{}
+	ApplyResources(hostCfg, &Resources{
+		MemorySwap:        -1,
+		MemoryReservation: 256,
+		CPUSet:            "0-1",
+		ShmSize:           64,
+		Ulimits:           []config.Ulimit{{Name: "nofile", Soft: 1024, Hard: 4096}},
+		BlkioWeight:       300,
+		StorageSize:       2048,
+	})
+
+	if hostCfg.Resources.MemorySwap != -1 {
+		t.Errorf("expected unlimited swap, got %d", hostCfg.Resources.MemorySwap)
+	}
+	if hostCfg.Resources.MemoryReservation != 256*1024*1024 {
+		t.Errorf("expected memory reservation 256 MB, got %d", hostCfg.Resources.MemoryReservation)
+	}
+	if hostCfg.Resources.CpusetCpus != "0-1" {
+		t.Errorf("expected cpu set 0-1, got %s", hostCfg.Resources.CpusetCpus)
+	}
+	if hostCfg.ShmSize != 64*1024*1024 {
+		t.Errorf("expected shm size 64 MB, got %d", hostCfg.ShmSize)
+	}
+	if len(hostCfg.Resources.Ulimits) != 1 || hostCfg.Resources.Ulimits[0].Name != "nofile" || hostCfg.Resources.Ulimits[0].Hard != 4096 {
+		t.Errorf("unexpected ulimits: %+v", hostCfg.Resources.Ulimits)
+	}
+	if hostCfg.Resources.BlkioWeight != 300 {
+		t.Errorf("expected blkio weight 300, got %d", hostCfg.Resources.BlkioWeight)
+	}
+	if hostCfg.StorageOpt["size"] != "2048M" {
+		t.Errorf("expected storage size 2048M, got %v", hostCfg.StorageOpt)
+	}
+}
@@ -0,0 +1,232 @@
+package dockerapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/go-zoox/command/config"
+)
+
+// defaultHealthTimeout is the time to wait for a service to be healthy unless configured.
+const defaultHealthTimeout = 60 * time.Second
+
+// healthInterval is the interval between the health checks of a service.
+const healthInterval = time.Second
+
+// Services are the service containers of a run on its private network.
+type Services struct {
+	client   *dockerClient.Client
+	id       string
+	services []config.Service
+	// pull pulls the image of a service as required by the pull policy
+	pull   func(ref string) error
+	prefix string
+	log    io.Writer
+	//
+	network    string
+	containers []string
+	once       sync.Once
+}
+
+// NewServices creates the services of the run with the id, pulling their images with pull.
+func NewServices(client *dockerClient.Client, id string, services []config.Service, pull func(ref string) error, prefix string, log io.Writer) *Services {
+	return &Services{
+		client:   client,
+		id:       id,
+		services: services,
+		pull:     pull,
+		prefix:   prefix,
+		log:      log,
+	}
+}
+
+// Create creates the private network of the run and starts the service
+// containers on it, with their names as aliases, waiting until they are healthy.
+// A container without network joins the private network only, so it reaches the services.
+func (s *Services) Create(hostCfg *container.HostConfig, networkCfg *network.NetworkingConfig) error {
+	name := s.networkName()
+	logf(s.log, s.prefix, "create services network %s ...", name)
+	created, err := s.client.NetworkCreate(context.Background(), name, network.CreateOptions{
+		Driver:   "bridge",
+		Internal: true,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: create services network: %w", s.prefix, err)
+	}
+	s.network = created.ID
+
+	for i := range s.services {
+		if err := s.start(&s.services[i]); err != nil {
+			return fmt.Errorf("%s: start service %s: %w", s.prefix, s.services[i].Name, err)
+		}
+	}
+
+	for i, id := range s.containers {
+		if err := s.wait(&s.services[i], id); err != nil {
+			return fmt.Errorf("%s: %w", s.prefix, err)
+		}
+	}
+
+	if hostCfg.NetworkMode == "none" {
+		hostCfg.NetworkMode = container.NetworkMode(name)
+		networkCfg.EndpointsConfig = map[string]*network.EndpointSettings{
+			name: {NetworkID: created.ID},
+		}
+	}
+
+	return nil
+}
+
+// networkName returns the name of the private network of the run.
+func (s *Services) networkName() string {
+	return fmt.Sprintf("%s_services", s.id)
+}
+
+// start creates and starts the container of the service.
+func (s *Services) start(service *config.Service) error {
+	if err := s.pull(service.Image); err != nil {
+		return err
+	}
+
+	env := []string{}
+	for k, v := range service.Environment {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	logf(s.log, s.prefix, "start service %s (%s) ...", service.Name, service.Image)
+	created, err := s.client.ContainerCreate(context.Background(), &container.Config{
+		Hostname: service.Name,
+		Image:    service.Image,
+		Cmd:      service.Command,
+		Env:      env,
+	}, &container.HostConfig{
+		NetworkMode: container.NetworkMode(s.networkName()),
+	}, &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			s.networkName(): {
+				NetworkID: s.network,
+				Aliases:   []string{service.Name},
+			},
+		},
+	}, nil, fmt.Sprintf("%s_%s", s.id, service.Name))
+	if err != nil {
+		return err
+	}
+	s.containers = append(s.containers, created.ID)
+
+	return s.client.ContainerStart(context.Background(), created.ID, container.StartOptions{})
+}
+
+// wait waits until the service runs and its health check succeeds.
+func (s *Services) wait(service *config.Service, id string) error {
+	timeout := service.HealthTimeout
+	if timeout == 0 {
+		timeout = defaultHealthTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		inspect, err := s.client.ContainerInspect(context.Background(), id)
+		if err != nil {
+			return err
+		}
+		if !inspect.State.Running {
+			return fmt.Errorf("service %s exited with status %d", service.Name, inspect.State.ExitCode)
+		}
+
+		if service.HealthCheck == "" {
+			return nil
+		}
+
+		healthy, err := s.check(id, service.HealthCheck, deadline)
+		if err != nil {
+			return err
+		}
+		if healthy {
+			logf(s.log, s.prefix, "service %s is healthy", service.Name)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("service %s is not healthy after %s", service.Name, timeout)
+		}
+		time.Sleep(healthInterval)
+	}
+}
+
+// check runs the health check in the service container until it exits or the
+// deadline passes, and reports whether it succeeded.
+func (s *Services) check(id, check string, deadline time.Time) (bool, error) {
+	exec, err := s.client.ContainerExecCreate(context.Background(), id, container.ExecOptions{
+		Cmd: []string{"/bin/sh", "-c", check},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.client.ContainerExecStart(context.Background(), exec.ID, container.ExecStartOptions{Detach: true}); err != nil {
+		return false, err
+	}
+
+	for time.Now().Before(deadline) {
+		inspect, err := s.client.ContainerExecInspect(context.Background(), exec.ID)
+		if err != nil {
+			return false, err
+		}
+		if !inspect.Running {
+			return inspect.ExitCode == 0, nil
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return false, nil
+}
+
+// Connect connects the created container to the private network of the run,
+// unless it is its only network. Nil services are ignored.
+func (s *Services) Connect(hostCfg *container.HostConfig, containerID string) error {
+	if s == nil || s.network == "" || hostCfg.NetworkMode == container.NetworkMode(s.networkName()) {
+		return nil
+	}
+
+	if err := s.client.NetworkConnect(context.Background(), s.network, containerID, nil); err != nil {
+		return fmt.Errorf("%s: connect services network: %w", s.prefix, err)
+	}
+
+	return nil
+}
+
+// Remove removes the service containers and the private network of the run,
+// disconnecting the container if it is kept. Nil services are ignored.
+func (s *Services) Remove(containerID string) {
+	if s == nil {
+		return
+	}
+
+	s.once.Do(func() {
+		for _, id := range s.containers {
+			if err := s.client.ContainerRemove(context.Background(), id, container.RemoveOptions{
+				Force:         true,
+				RemoveVolumes: true,
+			}); err != nil {
+				logf(s.log, s.prefix, "failed to remove service container: %s", err)
+			}
+		}
+
+		if s.network != "" {
+			if containerID != "" {
+				s.client.NetworkDisconnect(context.Background(), s.network, containerID, true)
+			}
+			if err := s.client.NetworkRemove(context.Background(), s.network); err != nil {
+				logf(s.log, s.prefix, "failed to remove services network: %s", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package dockerapi
+
+import (
+	dockerClient "github.com/docker/docker/client"
+	"github.com/go-zoox/command/engine"
+)
+
+// SessionKeepAliveCommand keeps the session container running until it is removed.
+const SessionKeepAliveCommand = "while :; do sleep 3600; done"
+
+// sessionStateDir is where the session state is kept inside the container.
+const sessionStateDir = "/tmp/.go-zoox-command-session"
+
+// Session executes every command inside one running container with the exec API.
+type Session struct {
+	client      *dockerClient.Client
+	containerID string
+	prefix      string
+	// remove removes the session container
+	remove func() error
+}
+
+// NewSession creates a session in the running container, which remove removes on Close.
+func NewSession(client *dockerClient.Client, containerID, prefix string, remove func() error) *Session {
+	return &Session{
+		client:      client,
+		containerID: containerID,
+		prefix:      prefix,
+		remove:      remove,
+	}
+}
+
+// Exec creates a command running in the session container.
+func (s *Session) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	return NewProcess(s.client, s.containerID, s.prefix, &engine.ExecConfig{
+		Command:     engine.WrapSessionCommand(sessionStateDir, cfg.Command),
+		Environment: cfg.Environment,
+		WorkDir:     cfg.WorkDir,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		ReadOnly:    cfg.ReadOnly,
+	}), nil
+}
+
+// Close removes the session container.
+func (s *Session) Close() error {
+	return s.remove()
+}
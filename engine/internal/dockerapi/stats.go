@@ -0,0 +1,132 @@
+package dockerapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/go-zoox/command/result"
+)
+
+// StatsSampler follows the stats stream of a container, keeping the latest sample
+// and the peak memory, so the summary survives the removal of the container.
+type StatsSampler struct {
+	sync.Mutex
+	//
+	stats *result.Stats
+	err   error
+	done  chan struct{}
+}
+
+// SampleStats starts following the stats stream of the container.
+func SampleStats(c *client.Client, containerID, prefix string) *StatsSampler {
+	s := &StatsSampler{
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.done)
+
+		resp, err := c.ContainerStats(context.Background(), containerID, true)
+		if err != nil {
+			s.fail(fmt.Errorf("%s: container stats: %w", prefix, err))
+			return
+		}
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var sample container.StatsResponse
+			if err := decoder.Decode(&sample); err != nil {
+				return
+			}
+
+			s.add(&sample)
+		}
+	}()
+
+	return s
+}
+
+func (s *StatsSampler) fail(err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.err = err
+}
+
+func (s *StatsSampler) add(sample *container.StatsResponse) {
+	// the final sample of a stopped container is empty
+	if sample.Read.IsZero() || sample.CPUStats.CPUUsage.TotalUsage == 0 {
+		return
+	}
+
+	stats := &result.Stats{
+		CPUTime:    time.Duration(sample.CPUStats.CPUUsage.TotalUsage),
+		UserTime:   time.Duration(sample.CPUStats.CPUUsage.UsageInUsermode),
+		SystemTime: time.Duration(sample.CPUStats.CPUUsage.UsageInKernelmode),
+		Memory:     memoryUsage(&sample.MemoryStats),
+	}
+	stats.PeakMemory = max(stats.Memory, int64(sample.MemoryStats.MaxUsage))
+
+	for _, entry := range sample.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockRead += int64(entry.Value)
+		case "write":
+			stats.BlockWrite += int64(entry.Value)
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stats != nil {
+		stats.PeakMemory = max(stats.PeakMemory, s.stats.PeakMemory)
+	}
+	s.stats = stats
+}
+
+// memoryUsage returns the memory usage without the page cache, like `docker stats`.
+func memoryUsage(m *container.MemoryStats) int64 {
+	usage := m.Usage
+	if v, ok := m.Stats["total_inactive_file"]; ok && v < usage {
+		// cgroup v1
+		usage -= v
+	} else if v, ok := m.Stats["inactive_file"]; ok && v < usage {
+		// cgroup v2
+		usage -= v
+	}
+
+	return int64(usage)
+}
+
+// Wait waits for the stream to end after the container exits, at most timeout.
+func (s *StatsSampler) Wait(timeout time.Duration) {
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+	}
+}
+
+// Get returns the latest sample.
+func (s *StatsSampler) Get() (*result.Stats, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stats == nil {
+		if s.err != nil {
+			return nil, s.err
+		}
+
+		return &result.Stats{}, nil
+	}
+
+	stats := *s.stats
+	return &stats, nil
+}
@@ -8,6 +8,10 @@ import (
 
 // Cancel deletes the Job (and its Pods via cascade).
 func (k *k8s) Cancel() error {
+	if k.stats != nil {
+		k.stats.stop()
+	}
+
 	ctx := context.Background()
 	propagation := metav1.DeletePropagationForeground
 	return k.clientset.BatchV1().Jobs(k.jobNamespace).Delete(ctx, k.jobName, metav1.DeleteOptions{
@@ -1,5 +1,7 @@
 package k8s
 
+import "github.com/go-zoox/command/config"
+
 // Config is the configuration for the k8s engine.
 type Config struct {
 	Command     string
@@ -10,6 +12,10 @@ type Config struct {
 	// ReadOnly means none-interactive for terminal, which is used for show log, like top
 	ReadOnly bool
 
+	// Script is mounted from a ConfigMap and run by Interpreter instead of Command
+	Script      string
+	Interpreter string
+
 	// engine = k8s
 	// Kubeconfig is the path to kubeconfig file (optional, uses in-cluster or default rules if empty)
 	Kubeconfig string
@@ -19,6 +25,28 @@ type Config struct {
 	Image string
 	// JobTimeoutSeconds is the optional timeout for the Job (0 = no timeout)
 	JobTimeoutSeconds int64
+	// PullPolicy is the image pull policy of the container: Always, IfNotPresent or Never
+	PullPolicy string
+
+	// Memory is the memory limit, unit: MB
+	Memory int64
+	// CPU is the CPU limit, unit: core
+	CPU float64
+	// MemoryReservation is the memory request, unit: MB
+	MemoryReservation int64
+	// ShmSize is the size of the memory-backed /dev/shm, unit: MB
+	ShmSize int64
+	// StorageSize is the ephemeral-storage limit, unit: MB
+	StorageSize int64
+	// DisableNetwork isolates the pod with a deny-all NetworkPolicy
+	DisableNetwork bool
+	// Mounts are mounted as hostPath (bind), PersistentVolumeClaim (named volume) and emptyDir (anonymous volume, tmpfs) volumes
+	Mounts []config.Mount
+
+	// Sandbox enables strict security settings for untrusted code
+	Sandbox bool
+	// SandboxProfile is the profile of sandbox mode, default: standard
+	SandboxProfile *config.SandboxProfile
 
 	// Custom Command Runner ID (used as Job name prefix)
 	ID string
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/go-zoox/command/engine"
+)
+
+// CopyTo extracts content into the directory dst of the Job's Pod.
+func (k *k8s) CopyTo(ctx context.Context, dst string, content io.Reader) error {
+	podName, err := k.runningPod(ctx)
+	if err != nil {
+		return err
+	}
+
+	return newProcess(k.clientset, k.restConfig, k.jobNamespace, podName, &engine.ExecConfig{}).CopyTo(ctx, dst, content)
+}
+
+// CopyFrom archives the file or directory src of the Job's Pod.
+func (k *k8s) CopyFrom(ctx context.Context, src string) (io.ReadCloser, error) {
+	podName, err := k.runningPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProcess(k.clientset, k.restConfig, k.jobNamespace, podName, &engine.ExecConfig{}).CopyFrom(ctx, src)
+}
+
+// CopyTo extracts content into the directory dst of the Pod with tar,
+// which has to be available in the image.
+func (p *process) CopyTo(ctx context.Context, dst string, content io.Reader) error {
+	quoted := engine.ShellQuote(dst)
+	p.cfg.Command = fmt.Sprintf("mkdir -p %s && tar xf - -C %s", quoted, quoted)
+
+	stderr := &bytes.Buffer{}
+	p.stdin = content
+	p.stdout = io.Discard
+	p.stderr = stderr
+
+	if err := p.run(ctx); err != nil {
+		return fmt.Errorf("k8s: copy to pod: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// CopyFrom archives the file or directory src of the Pod with tar,
+// which has to be available in the image.
+func (p *process) CopyFrom(ctx context.Context, src string) (io.ReadCloser, error) {
+	src = path.Clean(src)
+	p.cfg.Command = fmt.Sprintf("tar cf - -C %s %s", engine.ShellQuote(path.Dir(src)), engine.ShellQuote(path.Base(src)))
+
+	pr, pw := io.Pipe()
+	stderr := &bytes.Buffer{}
+	p.stdin = nil
+	p.stdout = pw
+	p.stderr = stderr
+
+	go func() {
+		if err := p.run(ctx); err != nil {
+			pw.CloseWithError(fmt.Errorf("k8s: copy from pod: %w: %s", err, strings.TrimSpace(stderr.String())))
+			return
+		}
+
+		pw.Close()
+	}()
+
+	return pr, nil
+}
+
+// run runs the process to completion, cancelling it when ctx is done.
+func (p *process) run(ctx context.Context) error {
+	if err := p.Start(); err != nil {
+		return err
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		p.Cancel()
+	})
+	defer stop()
+
+	return p.Wait()
+}
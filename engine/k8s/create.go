@@ -16,47 +16,19 @@ import (
 
 // create creates the Job (and stores clientset/config for later use).
 func (k *k8s) create() error {
-	var restConfig *rest.Config
-	var err error
-
-	if k.cfg.Kubeconfig != "" {
-		restConfig, err = clientcmd.BuildConfigFromFlags("", k.cfg.Kubeconfig)
-	} else {
-		restConfig, err = clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
-	}
-	if err != nil {
-		restConfig, err = rest.InClusterConfig()
-	}
-	if err != nil {
-		return fmt.Errorf("k8s: build config: %w", err)
+	if err := k.connect(); err != nil {
+		return err
 	}
 
-	clientset, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		return fmt.Errorf("k8s: create clientset: %w", err)
-	}
-
-	k.clientset = clientset
-	k.restConfig = restConfig
+	jobName := k.name()
+	envVars := k.envVars()
 
-	jobName := k.cfg.ID
-	if jobName == "" {
-		jobName = "go-zoox-command"
-	}
-	// Ensure valid DNS label (lowercase, alphanumeric, hyphen)
-	jobName = strings.ToLower(strings.ReplaceAll(jobName, "_", "-"))
-	if len(jobName) > 52 {
-		jobName = jobName[:52]
-	}
-
-	envVars := []corev1.EnvVar{}
-	for key, val := range k.cfg.Environment {
-		envVars = append(envVars, corev1.EnvVar{Name: key, Value: val})
-	}
-	for _, key := range k.cfg.AllowedSystemEnvKeys {
-		if val, ok := os.LookupEnv(key); ok {
-			envVars = append(envVars, corev1.EnvVar{Name: key, Value: val})
+	if k.cfg.Script != "" {
+		if err := k.createScript(jobName); err != nil {
+			return err
 		}
+
+		k.cfg.Command = k.scriptCommand()
 	}
 
 	args := []string{"-c", k.cfg.Command}
@@ -80,19 +52,24 @@ func (k *k8s) create() error {
 			ActiveDeadlineSeconds:   &activeDeadlineSeconds,
 			TTLSecondsAfterFinished: ptr(int32(300)),
 			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{nameLabel: jobName},
+				},
 				Spec: corev1.PodSpec{
 					RestartPolicy: corev1.RestartPolicyNever,
 					Containers: []corev1.Container{
 						{
-							Name:       "cmd",
-							Image:      k.cfg.Image,
-							Command:    []string{k.cfg.Shell},
-							Args:       args,
-							Env:        envVars,
-							WorkingDir: k.cfg.WorkDir,
-							Stdin:      true,
-							StdinOnce:  true,
-							TTY:        true,
+							Name:            "cmd",
+							Image:           k.cfg.Image,
+							ImagePullPolicy: corev1.PullPolicy(k.cfg.PullPolicy),
+							Command:         []string{k.cfg.Shell},
+							Args:            args,
+							Env:             envVars,
+							WorkingDir:      k.cfg.WorkDir,
+							Stdin:           true,
+							StdinOnce:       true,
+							TTY:             true,
+							Resources:       k.resources(),
 						},
 					},
 				},
@@ -100,14 +77,147 @@ func (k *k8s) create() error {
 		},
 	}
 
-	_, err = k.clientset.BatchV1().Jobs(k.cfg.Namespace).Create(context.Background(), job, metav1.CreateOptions{})
+	if k.cfg.Script != "" {
+		volume, mount := scriptVolume(jobName)
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volume)
+		job.Spec.Template.Spec.Containers[0].VolumeMounts = append(job.Spec.Template.Spec.Containers[0].VolumeMounts, mount)
+	}
+
+	if err := k.applyMounts(&job.Spec.Template.Spec); err != nil {
+		if k.cfg.Script != "" {
+			k.deleteScript(jobName)
+		}
+
+		return err
+	}
+
+	if k.cfg.Sandbox {
+		if err := k.applySandbox(&job.Spec.Template.Spec); err != nil {
+			if k.cfg.Script != "" {
+				k.deleteScript(jobName)
+			}
+
+			return err
+		}
+	}
+
+	// the pod must not start before its traffic is denied
+	if k.cfg.DisableNetwork {
+		if err := k.createNetworkPolicy(jobName); err != nil {
+			if k.cfg.Script != "" {
+				k.deleteScript(jobName)
+			}
+
+			return err
+		}
+	}
+
+	created, err := k.clientset.BatchV1().Jobs(k.cfg.Namespace).Create(context.Background(), job, metav1.CreateOptions{})
 	if err != nil {
+		if k.cfg.Script != "" {
+			k.deleteScript(jobName)
+		}
+		if k.cfg.DisableNetwork {
+			k.deleteNetworkPolicy(jobName)
+		}
+
 		return fmt.Errorf("k8s: create job: %w", err)
 	}
 
+	if k.cfg.Script != "" {
+		if err := k.ownScript(created); err != nil {
+			k.deleteJob(jobName)
+			k.deleteScript(jobName)
+			if k.cfg.DisableNetwork {
+				k.deleteNetworkPolicy(jobName)
+			}
+
+			return err
+		}
+	}
+	if k.cfg.DisableNetwork {
+		if err := k.ownNetworkPolicy(jobName, metav1.OwnerReference{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+			Name:       created.Name,
+			UID:        created.UID,
+		}); err != nil {
+			// the script is owned by the job already, the policy may not be
+			k.deleteJob(jobName)
+			k.deleteNetworkPolicy(jobName)
+
+			return err
+		}
+	}
+
 	k.jobName = jobName
 	k.jobNamespace = k.cfg.Namespace
 	return nil
 }
 
+// deleteJob deletes a job which cannot run as configured, with its pods.
+func (k *k8s) deleteJob(name string) {
+	propagation := metav1.DeletePropagationBackground
+	k.clientset.BatchV1().Jobs(k.cfg.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+}
+
 func ptr(i int32) *int32 { return &i }
+
+// connect builds the clientset from kubeconfig or in-cluster config.
+func (k *k8s) connect() error {
+	var restConfig *rest.Config
+	var err error
+
+	if k.cfg.Kubeconfig != "" {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", k.cfg.Kubeconfig)
+	} else {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+	}
+	if err != nil {
+		restConfig, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return fmt.Errorf("k8s: build config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("k8s: create clientset: %w", err)
+	}
+
+	k.clientset = clientset
+	k.restConfig = restConfig
+	return nil
+}
+
+// name returns the resource name derived from the command ID.
+func (k *k8s) name() string {
+	name := k.cfg.ID
+	if name == "" {
+		name = "go-zoox-command"
+	}
+	// Ensure valid DNS label (lowercase, alphanumeric, hyphen)
+	name = strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+	if len(name) > 52 {
+		name = name[:52]
+	}
+
+	return name
+}
+
+// envVars returns the container environment variables.
+func (k *k8s) envVars() []corev1.EnvVar {
+	envVars := []corev1.EnvVar{}
+	for key, val := range k.cfg.Environment {
+		envVars = append(envVars, corev1.EnvVar{Name: key, Value: val})
+	}
+	for _, key := range k.cfg.AllowedSystemEnvKeys {
+		if val, ok := os.LookupEnv(key); ok {
+			envVars = append(envVars, corev1.EnvVar{Name: key, Value: val})
+		}
+	}
+
+	return envVars
+}
@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-zoox/command/engine"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Exec creates a process running inside the Job's Pod.
+func (k *k8s) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	podName, err := k.runningPod(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return newProcess(k.clientset, k.restConfig, k.jobNamespace, podName, cfg), nil
+}
+
+// runningPod returns the name of the running Pod of the Job.
+func (k *k8s) runningPod(ctx context.Context) (string, error) {
+	pods, err := k.clientset.CoreV1().Pods(k.jobNamespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + k.jobName})
+	if err != nil {
+		return "", fmt.Errorf("k8s: list pods: %w", err)
+	}
+
+	for _, p := range pods.Items {
+		if p.Status.Phase == corev1.PodRunning {
+			return p.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("k8s: no running pod for job %s", k.jobName)
+}
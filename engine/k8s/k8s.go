@@ -20,6 +20,8 @@ type k8s struct {
 	jobNamespace string
 	jobName      string
 	//
+	stats *statsSampler
+	//
 	stdin  io.Reader
 	stdout io.Writer
 	stderr io.Writer
@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/go-zoox/command/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// shmVolumeName is the name of the memory-backed volume mounted at /dev/shm.
+const shmVolumeName = "go-zoox-command-shm"
+
+// applyMounts adds the volumes of the configured mounts to the pod: hostPath for
+// bind mounts, the PersistentVolumeClaim of named volumes, emptyDirs for anonymous
+// volumes and memory-backed emptyDirs for tmpfs mounts and /dev/shm.
+func (k *k8s) applyMounts(spec *corev1.PodSpec) error {
+	container := &spec.Containers[0]
+
+	for index, m := range k.cfg.Mounts {
+		name := fmt.Sprintf("go-zoox-command-mount-%d", index)
+
+		var source corev1.VolumeSource
+		switch m.Type {
+		case config.MountVolume:
+			if m.Source == "" {
+				source.EmptyDir = &corev1.EmptyDirVolumeSource{}
+			} else {
+				source.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: m.Source,
+					ReadOnly:  m.ReadOnly,
+				}
+			}
+		case config.MountTmpfs:
+			if m.TmpfsMode != 0 {
+				return fmt.Errorf("k8s: tmpfs mode is not supported: %s", m.Target)
+			}
+
+			source.EmptyDir = &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}
+			if m.TmpfsSize != 0 {
+				source.EmptyDir.SizeLimit = resource.NewQuantity(m.TmpfsSize, resource.BinarySI)
+			}
+		default:
+			source.HostPath = &corev1.HostPathVolumeSource{Path: m.Source}
+		}
+
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name:         name,
+			VolumeSource: source,
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: m.Target,
+			ReadOnly:  m.ReadOnly,
+		})
+	}
+
+	if k.cfg.ShmSize != 0 {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: shmVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: resource.NewQuantity(k.cfg.ShmSize*1024*1024, resource.BinarySI),
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      shmVolumeName,
+			MountPath: "/dev/shm",
+		})
+	}
+
+	return nil
+}
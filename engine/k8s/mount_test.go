@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/go-zoox/command/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestApplyMounts(t *testing.T) {
+	k := &k8s{cfg: &Config{Mounts: []config.Mount{
+		{Type: config.MountBind, Source: "/data", Target: "/data", ReadOnly: true},
+		{Type: config.MountVolume, Source: "cache", Target: "/cache"},
+		{Type: config.MountVolume, Target: "/scratch"},
+		{Type: config.MountTmpfs, Target: "/tmp", TmpfsSize: 64 << 20},
+	}}}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: containerName}}}
+	if err := k.applyMounts(spec); err != nil {
+		t.Fatalf("applyMounts: %v", err)
+	}
+
+	if len(spec.Volumes) != 4 || len(spec.Containers[0].VolumeMounts) != 4 {
+		t.Fatalf("expected 4 volumes, got %+v", spec.Volumes)
+	}
+
+	if v := spec.Volumes[0].HostPath; v == nil || v.Path != "/data" || !spec.Containers[0].VolumeMounts[0].ReadOnly {
+		t.Errorf("unexpected bind mount: %+v", spec.Volumes[0])
+	}
+	if v := spec.Volumes[1].PersistentVolumeClaim; v == nil || v.ClaimName != "cache" {
+		t.Errorf("unexpected named volume: %+v", spec.Volumes[1])
+	}
+	if v := spec.Volumes[2].EmptyDir; v == nil || v.Medium != corev1.StorageMediumDefault {
+		t.Errorf("unexpected anonymous volume: %+v", spec.Volumes[2])
+	}
+	if v := spec.Volumes[3].EmptyDir; v == nil || v.Medium != corev1.StorageMediumMemory || v.SizeLimit.String() != "64Mi" {
+		t.Errorf("unexpected tmpfs mount: %+v", spec.Volumes[3])
+	}
+	if m := spec.Containers[0].VolumeMounts[3]; m.MountPath != "/tmp" || m.Name != spec.Volumes[3].Name {
+		t.Errorf("unexpected tmpfs volume mount: %+v", m)
+	}
+}
+
+func TestApplyMounts_TmpfsMode(t *testing.T) {
+	k := &k8s{cfg: &Config{Mounts: []config.Mount{{Type: config.MountTmpfs, Target: "/tmp", TmpfsMode: 01777}}}}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: containerName}}}
+	if err := k.applyMounts(spec); err == nil {
+		t.Error("expected error for tmpfs mode")
+	}
+}
+
+func TestApplyMounts_Shm(t *testing.T) {
+	k := &k8s{cfg: &Config{ShmSize: 256}}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: containerName}}}
+	if err := k.applyMounts(spec); err != nil {
+		t.Fatalf("applyMounts: %v", err)
+	}
+
+	if len(spec.Volumes) != 1 || spec.Volumes[0].EmptyDir == nil || spec.Volumes[0].EmptyDir.SizeLimit.String() != "256Mi" {
+		t.Fatalf("unexpected shm volume: %+v", spec.Volumes)
+	}
+	if m := spec.Containers[0].VolumeMounts[0]; m.MountPath != "/dev/shm" {
+		t.Errorf("unexpected shm volume mount: %+v", m)
+	}
+}
@@ -0,0 +1,312 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-zoox/command/engine"
+	cmderrors "github.com/go-zoox/command/errors"
+	"github.com/go-zoox/command/terminal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// process is a process executed inside an existing Pod with the exec subresource.
+type process struct {
+	cfg *engine.ExecConfig
+	//
+	clientset  kubernetes.Interface
+	restConfig *rest.Config
+	namespace  string
+	podName    string
+	//
+	done   chan error
+	cancel context.CancelFunc
+	//
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func newProcess(clientset kubernetes.Interface, restConfig *rest.Config, namespace, podName string, cfg *engine.ExecConfig) *process {
+	if cfg.Shell == "" {
+		cfg.Shell = "/bin/sh"
+	}
+
+	return &process{
+		cfg: cfg,
+		//
+		clientset:  clientset,
+		restConfig: restConfig,
+		namespace:  namespace,
+		podName:    podName,
+		//
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+}
+
+// command returns the exec command. PodExecOptions cannot carry environment
+// variables or a working directory, so the shell sets them up instead.
+func (p *process) command() []string {
+	keys := make([]string, 0, len(p.cfg.Environment))
+	for key := range p.cfg.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	script := &strings.Builder{}
+	for _, key := range keys {
+		fmt.Fprintf(script, "export %s=%s\n", key, engine.ShellQuote(p.cfg.Environment[key]))
+	}
+	if p.cfg.WorkDir != "" {
+		fmt.Fprintf(script, "cd %s || exit 1\n", engine.ShellQuote(p.cfg.WorkDir))
+	}
+	script.WriteString(p.cfg.Command)
+
+	return []string{p.cfg.Shell, "-c", script.String()}
+}
+
+func (p *process) executor(tty bool) (remotecommand.Executor, error) {
+	req := p.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(p.namespace).
+		Name(p.podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   p.command(),
+			Stdin:     tty || p.stdin != nil,
+			Stdout:    true,
+			Stderr:    !tty,
+			TTY:       tty,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(p.restConfig, "POST", req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("k8s: new exec executor: %w", err)
+	}
+
+	return executor, nil
+}
+
+// Start starts the process.
+func (p *process) Start() error {
+	executor, err := p.executor(false)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan error, 1)
+	go func() {
+		p.done <- executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+			Stdin:  p.stdin,
+			Stdout: p.stdout,
+			Stderr: p.stderr,
+		})
+	}()
+
+	return nil
+}
+
+// Wait waits for the process to exit.
+func (p *process) Wait() error {
+	if p.done == nil {
+		return fmt.Errorf("k8s: process is not started")
+	}
+
+	return toExitError(<-p.done)
+}
+
+// Cancel cancels the process by closing its stream.
+func (p *process) Cancel() error {
+	if p.cancel != nil {
+		p.cancel()
+	}
+
+	return nil
+}
+
+// SetStdin sets the stdin for the process.
+func (p *process) SetStdin(stdin io.Reader) error {
+	p.stdin = stdin
+	return nil
+}
+
+// SetStdout sets the stdout for the process.
+func (p *process) SetStdout(stdout io.Writer) error {
+	p.stdout = stdout
+	return nil
+}
+
+// SetStderr sets the stderr for the process.
+func (p *process) SetStderr(stderr io.Writer) error {
+	p.stderr = stderr
+	return nil
+}
+
+// Terminal returns a terminal for the process.
+func (p *process) Terminal() (terminal.Terminal, error) {
+	executor, err := p.executor(true)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+
+	t := &ExecTerminal{
+		ReadOnly: p.cfg.ReadOnly,
+		//
+		stdin:  stdinWriter,
+		stdout: stdoutReader,
+		sizes:  make(chan *remotecommand.TerminalSize, 1),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer close(t.done)
+		defer stdoutWriter.Close()
+		defer stdinReader.Close()
+
+		t.err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+			Stdin:             stdinReader,
+			Stdout:            stdoutWriter,
+			Tty:               true,
+			TerminalSizeQueue: t,
+		})
+	}()
+
+	return t, nil
+}
+
+// ExecTerminal is the terminal implementation for a process executed inside a Pod.
+type ExecTerminal struct {
+	ReadOnly bool
+
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	sizes  chan *remotecommand.TerminalSize
+
+	done   chan struct{}
+	err    error
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Read reads from the exec stdout stream.
+func (t *ExecTerminal) Read(p []byte) (n int, err error) {
+	return t.stdout.Read(p)
+}
+
+// Write writes to the exec stdin stream (disabled when ReadOnly).
+func (t *ExecTerminal) Write(p []byte) (n int, err error) {
+	if t.ReadOnly {
+		return 0, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return 0, io.EOF
+	}
+	return t.stdin.Write(p)
+}
+
+// Close closes the exec stream.
+func (t *ExecTerminal) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	_ = t.stdin.Close()
+	t.cancel()
+	return nil
+}
+
+// Resize resizes the remote terminal.
+func (t *ExecTerminal) Resize(rows, cols int) error {
+	size := &remotecommand.TerminalSize{Width: uint16(cols), Height: uint16(rows)}
+	select {
+	case t.sizes <- size:
+	default:
+		// drop the stale size and keep the latest one
+		select {
+		case <-t.sizes:
+		default:
+		}
+		t.sizes <- size
+	}
+
+	return nil
+}
+
+// Next implements remotecommand.TerminalSizeQueue.
+func (t *ExecTerminal) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-t.sizes:
+		return size
+	case <-t.done:
+		return nil
+	}
+}
+
+// ExitCode returns the exit code after Wait.
+func (t *ExecTerminal) ExitCode() int {
+	select {
+	case <-t.done:
+	default:
+		return -1
+	}
+
+	if err, ok := toExitError(t.err).(*cmderrors.ExitError); ok {
+		return err.Code
+	}
+	if t.err != nil {
+		return -1
+	}
+
+	return 0
+}
+
+// Wait waits for the process to exit.
+func (t *ExecTerminal) Wait() error {
+	<-t.done
+	return toExitError(t.err)
+}
+
+// toExitError converts the exec stream error to an ExitError.
+func toExitError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if v, ok := err.(utilexec.ExitError); ok && v.Exited() {
+		return &cmderrors.ExitError{
+			Code:    v.ExitStatus(),
+			Message: fmt.Sprintf("process exited with status %d", v.ExitStatus()),
+		}
+	}
+
+	return fmt.Errorf("k8s: exec: %w", err)
+}
@@ -0,0 +1,209 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-zoox/command/sandbox"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nameLabel selects the pod of a command, e.g. for its NetworkPolicy.
+const nameLabel = "go-zoox.com/command"
+
+// nobody is the user sandboxed pods run as unless the config sets a numeric one.
+const nobody = int64(65534)
+
+// resources returns the resource limits of the container, and the memory
+// reservation as its request.
+func (k *k8s) resources() corev1.ResourceRequirements {
+	limits := corev1.ResourceList{}
+	if k.cfg.Memory != 0 {
+		limits[corev1.ResourceMemory] = *resource.NewQuantity(k.cfg.Memory*1024*1024, resource.BinarySI)
+	}
+	if k.cfg.CPU != 0 {
+		limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(k.cfg.CPU*1000), resource.DecimalSI)
+	}
+	if k.cfg.StorageSize != 0 {
+		limits[corev1.ResourceEphemeralStorage] = *resource.NewQuantity(k.cfg.StorageSize*1024*1024, resource.BinarySI)
+	}
+
+	requests := corev1.ResourceList{}
+	if k.cfg.MemoryReservation != 0 {
+		requests[corev1.ResourceMemory] = *resource.NewQuantity(k.cfg.MemoryReservation*1024*1024, resource.BinarySI)
+	}
+
+	resources := corev1.ResourceRequirements{}
+	if len(limits) != 0 {
+		resources.Limits = limits
+	}
+	if len(requests) != 0 {
+		resources.Requests = requests
+	}
+
+	return resources
+}
+
+// applySandbox applies the security settings of the sandbox profile to the pod:
+// non-root user, no privilege escalation, dropped capabilities, RuntimeDefault
+// seccomp, read-only root filesystem and memory-backed emptyDirs for the tmpfs mounts.
+func (k *k8s) applySandbox(spec *corev1.PodSpec) error {
+	profile := k.cfg.SandboxProfile
+	if profile == nil {
+		var err error
+		if profile, err = sandbox.Get(sandbox.Standard); err != nil {
+			return err
+		}
+	}
+
+	uid, gid := nobody, nobody
+	if k.cfg.User != "" {
+		var err error
+		if uid, gid, err = parseUser(k.cfg.User); err != nil {
+			return err
+		}
+	}
+
+	spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsNonRoot: ptrBool(true),
+		RunAsUser:    &uid,
+		RunAsGroup:   &gid,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+
+	capabilities := []corev1.Capability{}
+	for _, capability := range profile.Capabilities {
+		capabilities = append(capabilities, corev1.Capability(capability))
+	}
+
+	container := &spec.Containers[0]
+	container.SecurityContext = &corev1.SecurityContext{
+		Privileged:               ptrBool(false),
+		AllowPrivilegeEscalation: ptrBool(false),
+		ReadOnlyRootFilesystem:   ptrBool(profile.ReadOnlyRootfs),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+			Add:  capabilities,
+		},
+	}
+
+	paths := make([]string, 0, len(profile.Tmpfs))
+	for path := range profile.Tmpfs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for index, path := range paths {
+		name := fmt.Sprintf("go-zoox-command-tmpfs-%d", index)
+
+		emptyDir := &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}
+		for _, option := range strings.Split(profile.Tmpfs[path], ",") {
+			if size, ok := strings.CutPrefix(option, "size="); ok {
+				quantity, err := tmpfsSize(size)
+				if err != nil {
+					return err
+				}
+				emptyDir.SizeLimit = &quantity
+			}
+		}
+
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{EmptyDir: emptyDir},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: path,
+		})
+	}
+
+	return nil
+}
+
+// tmpfsSize converts the tmpfs size option (bytes or k, m, g suffixed) into a quantity.
+func tmpfsSize(size string) (resource.Quantity, error) {
+	value := strings.TrimSuffix(strings.ToLower(size), "b")
+	if n := len(value); n > 0 && strings.ContainsRune("kmg", rune(value[n-1])) {
+		value = value[:n-1] + strings.ToUpper(value[n-1:]) + "i"
+	}
+
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return quantity, fmt.Errorf("k8s: invalid tmpfs size %s: %w", size, err)
+	}
+
+	return quantity, nil
+}
+
+// parseUser parses a numeric uid[:gid], as runAsNonRoot requires a numeric user.
+func parseUser(user string) (uid, gid int64, err error) {
+	parts := strings.SplitN(user, ":", 2)
+	if uid, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("k8s: sandbox requires a numeric user, got: %s", user)
+	}
+
+	gid = uid
+	if len(parts) == 2 {
+		if gid, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("k8s: sandbox requires a numeric group, got: %s", user)
+		}
+	}
+
+	return uid, gid, nil
+}
+
+// createNetworkPolicy creates a NetworkPolicy denying all ingress and egress
+// traffic of the pod labeled with the name.
+func (k *k8s) createNetworkPolicy(name string) error {
+	_, err := k.clientset.NetworkingV1().NetworkPolicies(k.cfg.Namespace).Create(context.Background(), &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: k.cfg.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{nameLabel: name},
+			},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("k8s: create network policy: %w", err)
+	}
+
+	return nil
+}
+
+// ownNetworkPolicy makes the owner (Job or Pod) own the NetworkPolicy, so it is garbage collected with it.
+func (k *k8s) ownNetworkPolicy(name string, owner metav1.OwnerReference) error {
+	policies := k.clientset.NetworkingV1().NetworkPolicies(k.cfg.Namespace)
+	policy, err := policies.Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("k8s: get network policy: %w", err)
+	}
+
+	policy.OwnerReferences = append(policy.OwnerReferences, owner)
+	if _, err := policies.Update(context.Background(), policy, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("k8s: update network policy: %w", err)
+	}
+
+	return nil
+}
+
+// deleteNetworkPolicy deletes the NetworkPolicy.
+func (k *k8s) deleteNetworkPolicy(name string) {
+	k.clientset.NetworkingV1().NetworkPolicies(k.cfg.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
+
+func ptrBool(b bool) *bool { return &b }
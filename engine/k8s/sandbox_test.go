@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/go-zoox/command/sandbox"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestApplySandbox(t *testing.T) {
+	profile, err := sandbox.Get(sandbox.Standard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k := &k8s{cfg: &Config{Sandbox: true, SandboxProfile: profile, User: "1000:2000"}}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: containerName}}}
+	if err := k.applySandbox(spec); err != nil {
+		t.Fatalf("applySandbox: %v", err)
+	}
+
+	pod := spec.SecurityContext
+	if pod == nil || !*pod.RunAsNonRoot || *pod.RunAsUser != 1000 || *pod.RunAsGroup != 2000 {
+		t.Fatalf("unexpected pod security context: %+v", pod)
+	}
+	if pod.SeccompProfile == nil || pod.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Errorf("expected RuntimeDefault seccomp, got %+v", pod.SeccompProfile)
+	}
+
+	container := spec.Containers[0].SecurityContext
+	if container == nil || *container.AllowPrivilegeEscalation || !*container.ReadOnlyRootFilesystem {
+		t.Fatalf("unexpected container security context: %+v", container)
+	}
+	if len(container.Capabilities.Drop) != 1 || container.Capabilities.Drop[0] != "ALL" {
+		t.Errorf("expected all capabilities dropped, got %v", container.Capabilities.Drop)
+	}
+	if len(container.Capabilities.Add) != len(profile.Capabilities) {
+		t.Errorf("expected %d capabilities, got %v", len(profile.Capabilities), container.Capabilities.Add)
+	}
+
+	if len(spec.Volumes) != len(profile.Tmpfs) || len(spec.Containers[0].VolumeMounts) != len(profile.Tmpfs) {
+		t.Fatalf("expected %d tmpfs volumes, got %d", len(profile.Tmpfs), len(spec.Volumes))
+	}
+	for _, volume := range spec.Volumes {
+		if volume.EmptyDir == nil || volume.EmptyDir.Medium != corev1.StorageMediumMemory || volume.EmptyDir.SizeLimit.String() != "100Mi" {
+			t.Errorf("unexpected tmpfs volume: %+v", volume)
+		}
+	}
+}
+
+func TestApplySandbox_NonNumericUser(t *testing.T) {
+	k := &k8s{cfg: &Config{Sandbox: true, User: "nobody"}}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: containerName}}}
+	if err := k.applySandbox(spec); err == nil {
+		t.Fatal("expected error for non-numeric user")
+	}
+}
+
+func TestResources(t *testing.T) {
+	k := &k8s{cfg: &Config{Memory: 512, CPU: 0.5}}
+	limits := k.resources().Limits
+	if memory := limits[corev1.ResourceMemory]; memory.String() != "512Mi" {
+		t.Errorf("expected memory 512Mi, got %s", memory.String())
+	}
+	if cpu := limits[corev1.ResourceCPU]; cpu.String() != "500m" {
+		t.Errorf("expected cpu 500m, got %s", cpu.String())
+	}
+
+	if k := (&k8s{cfg: &Config{}}); k.resources().Limits != nil {
+		t.Errorf("expected no limits, got %v", k.resources().Limits)
+	}
+}
+
+func TestResources_RequestsAndStorage(t *testing.T) {
+	k := &k8s{cfg: &Config{Memory: 512, MemoryReservation: 256, StorageSize: 1024}}
+	resources := k.resources()
+	if memory := resources.Requests[corev1.ResourceMemory]; memory.String() != "256Mi" {
+		t.Errorf("expected memory request 256Mi, got %s", memory.String())
+	}
+	if storage := resources.Limits[corev1.ResourceEphemeralStorage]; storage.String() != "1Gi" {
+		t.Errorf("expected ephemeral-storage limit 1Gi, got %s", storage.String())
+	}
+}
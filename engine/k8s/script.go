@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/go-zoox/command/engine"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// scriptDir is where the ConfigMap holding the script is mounted.
+const scriptDir = "/.go-zoox-command"
+
+// scriptVolumeName is the name of the script volume.
+const scriptVolumeName = "go-zoox-command-script"
+
+// createScript creates the ConfigMap holding the script, limited to 1MiB by Kubernetes.
+func (k *k8s) createScript(name string) error {
+	_, err := k.clientset.CoreV1().ConfigMaps(k.cfg.Namespace).Create(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: k.cfg.Namespace,
+		},
+		Data: map[string]string{
+			engine.ScriptFileName: k.cfg.Script,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("k8s: create script configmap: %w", err)
+	}
+
+	return nil
+}
+
+// ownScript makes the Job own the script ConfigMap, so it is garbage collected with the Job.
+func (k *k8s) ownScript(job *batchv1.Job) error {
+	configMaps := k.clientset.CoreV1().ConfigMaps(k.cfg.Namespace)
+	configMap, err := configMaps.Get(context.Background(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("k8s: get script configmap: %w", err)
+	}
+
+	configMap.OwnerReferences = append(configMap.OwnerReferences, metav1.OwnerReference{
+		APIVersion: "batch/v1",
+		Kind:       "Job",
+		Name:       job.Name,
+		UID:        job.UID,
+	})
+	if _, err := configMaps.Update(context.Background(), configMap, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("k8s: update script configmap: %w", err)
+	}
+
+	return nil
+}
+
+// deleteScript deletes the script ConfigMap.
+func (k *k8s) deleteScript(name string) {
+	k.clientset.CoreV1().ConfigMaps(k.cfg.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
+
+// scriptVolume returns the volume and the mount of the script ConfigMap.
+func scriptVolume(name string) (corev1.Volume, corev1.VolumeMount) {
+	mode := int32(0755)
+	volume := corev1.Volume{
+		Name: scriptVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				DefaultMode:          &mode,
+			},
+		},
+	}
+	mount := corev1.VolumeMount{
+		Name:      scriptVolumeName,
+		MountPath: scriptDir,
+		ReadOnly:  true,
+	}
+
+	return volume, mount
+}
+
+// scriptCommand returns the command running the mounted script.
+func (k *k8s) scriptCommand() string {
+	return engine.ScriptCommand(k.cfg.Interpreter, path.Join(scriptDir, engine.ScriptFileName))
+}
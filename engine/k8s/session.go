@@ -0,0 +1,168 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-zoox/command/engine"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// sessionKeepAliveCommand keeps the session Pod running until it is deleted.
+const sessionKeepAliveCommand = "while :; do sleep 3600; done"
+
+// sessionStateDir is where the session state is kept inside the Pod.
+const sessionStateDir = "/tmp/.go-zoox-command-session"
+
+type session struct {
+	k8s *k8s
+	//
+	podName string
+}
+
+// NewSession creates a new k8s session, which keeps one Pod running
+// and executes every command inside it with the exec subresource.
+func NewSession(cfg *Config) (engine.Session, error) {
+	if cfg.Shell == "" {
+		cfg.Shell = "/bin/sh"
+	}
+	if cfg.Image == "" {
+		cfg.Image = "alpine:latest"
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = "default"
+	}
+
+	k := &k8s{
+		cfg: cfg,
+	}
+	if err := k.connect(); err != nil {
+		return nil, err
+	}
+
+	activeDeadlineSeconds := cfg.JobTimeoutSeconds
+	if activeDeadlineSeconds <= 0 {
+		activeDeadlineSeconds = 3600
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.name(),
+			Namespace: cfg.Namespace,
+			Labels:    map[string]string{nameLabel: k.name()},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Containers: []corev1.Container{
+				{
+					Name:            containerName,
+					Image:           cfg.Image,
+					ImagePullPolicy: corev1.PullPolicy(cfg.PullPolicy),
+					Command:         []string{cfg.Shell},
+					Args:            []string{"-c", sessionKeepAliveCommand},
+					Env:             k.envVars(),
+					WorkingDir:      cfg.WorkDir,
+					Resources:       k.resources(),
+				},
+			},
+		},
+	}
+
+	if err := k.applyMounts(&pod.Spec); err != nil {
+		return nil, err
+	}
+
+	if cfg.Sandbox {
+		if err := k.applySandbox(&pod.Spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.DisableNetwork {
+		if err := k.createNetworkPolicy(pod.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := context.Background()
+	created, err := k.clientset.CoreV1().Pods(cfg.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		if cfg.DisableNetwork {
+			k.deleteNetworkPolicy(pod.Name)
+		}
+
+		return nil, fmt.Errorf("k8s: create pod: %w", err)
+	}
+
+	s := &session{
+		k8s:     k,
+		podName: pod.Name,
+	}
+	if cfg.DisableNetwork {
+		if err := k.ownNetworkPolicy(pod.Name, metav1.OwnerReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       created.Name,
+			UID:        created.UID,
+		}); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	if err := s.waitForRunning(ctx, 5*time.Minute); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// waitForRunning waits for the session Pod to be Running.
+func (s *session) waitForRunning(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		pod, err := s.k8s.clientset.CoreV1().Pods(s.k8s.cfg.Namespace).Get(ctx, s.podName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("k8s: get pod: %w", err)
+		}
+
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			return nil
+		case corev1.PodSucceeded, corev1.PodFailed:
+			return fmt.Errorf("k8s: session pod %s exited (phase: %s)", s.podName, pod.Status.Phase)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	return fmt.Errorf("k8s: timeout waiting for session pod %s to be running", s.podName)
+}
+
+// Exec creates a command running in the session Pod.
+func (s *session) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	return newProcess(s.k8s.clientset, s.k8s.restConfig, s.k8s.cfg.Namespace, s.podName, &engine.ExecConfig{
+		Command:     engine.WrapSessionCommand(sessionStateDir, cfg.Command),
+		Environment: cfg.Environment,
+		WorkDir:     cfg.WorkDir,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		ReadOnly:    cfg.ReadOnly,
+	}), nil
+}
+
+// Close deletes the session Pod.
+func (s *session) Close() error {
+	gracePeriodSeconds := int64(0)
+	return s.k8s.clientset.CoreV1().Pods(s.k8s.cfg.Namespace).Delete(context.Background(), s.podName, metav1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriodSeconds,
+	})
+}
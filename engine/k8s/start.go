@@ -41,6 +41,8 @@ func (k *k8s) Start() error {
 		return fmt.Errorf("k8s: new attach executor: %w", err)
 	}
 
+	k.stats = k.sampleStats(podName)
+
 	// Run attach stream in goroutine so Start() can return and Wait() can wait for Job completion
 	go func() {
 		_ = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/command/result"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// statsInterval is the interval of polling pod metrics, matching the default metrics-server resolution.
+const statsInterval = 15 * time.Second
+
+// podMetrics is the subset of metrics.k8s.io/v1beta1 PodMetrics used for stats.
+type podMetrics struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Containers []struct {
+		Name  string `json:"name"`
+		Usage struct {
+			CPU    string `json:"cpu"`
+			Memory string `json:"memory"`
+		} `json:"usage"`
+	} `json:"containers"`
+}
+
+// statsSampler polls the metrics API while the Pod runs. The metrics API only reports
+// the current CPU rate and working set, so CPU time is estimated from the samples.
+type statsSampler struct {
+	sync.Mutex
+	//
+	stats    *result.Stats
+	err      error
+	lastRead time.Time
+	cancel   context.CancelFunc
+}
+
+// sampleStats starts polling the metrics of the Pod.
+func (k *k8s) sampleStats(podName string) *statsSampler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &statsSampler{cancel: cancel}
+
+	go func() {
+		ticker := time.NewTicker(statsInterval)
+		defer ticker.Stop()
+
+		for {
+			s.add(k.podMetrics(ctx, podName))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return s
+}
+
+// podMetrics reads the metrics of the Pod, available when metrics-server is installed.
+func (k *k8s) podMetrics(ctx context.Context, podName string) (*podMetrics, error) {
+	raw, err := k.clientset.CoreV1().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", k.jobNamespace, "pods", podName).
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("k8s: pod metrics are not available: %w", err)
+	}
+
+	metrics := &podMetrics{}
+	if err := json.Unmarshal(raw, metrics); err != nil {
+		return nil, fmt.Errorf("k8s: decode pod metrics: %w", err)
+	}
+
+	return metrics, nil
+}
+
+func (s *statsSampler) add(metrics *podMetrics, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err != nil {
+		if s.stats == nil {
+			s.err = err
+		}
+		return
+	}
+
+	for _, c := range metrics.Containers {
+		if c.Name != containerName {
+			continue
+		}
+
+		stats := &result.Stats{}
+		if s.stats != nil {
+			*stats = *s.stats
+		}
+
+		if memory, err := resource.ParseQuantity(c.Usage.Memory); err == nil {
+			stats.Memory = memory.Value()
+			stats.PeakMemory = max(stats.PeakMemory, stats.Memory)
+		}
+
+		if cpu, err := resource.ParseQuantity(c.Usage.CPU); err == nil && !s.lastRead.IsZero() {
+			elapsed := metrics.Timestamp.Sub(s.lastRead)
+			if elapsed > 0 {
+				stats.CPUTime += time.Duration(float64(elapsed) * cpu.AsApproximateFloat64())
+			}
+		}
+
+		s.lastRead = metrics.Timestamp
+		s.stats = stats
+		s.err = nil
+	}
+}
+
+func (s *statsSampler) stop() {
+	s.cancel()
+}
+
+func (s *statsSampler) get() (*result.Stats, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stats == nil {
+		if s.err != nil {
+			return nil, s.err
+		}
+
+		return &result.Stats{}, nil
+	}
+
+	stats := *s.stats
+	return &stats, nil
+}
+
+// Stats returns the memory usage and estimated CPU time of the Pod from the metrics API.
+func (k *k8s) Stats() (*result.Stats, error) {
+	if k.stats == nil {
+		return nil, errors.New("k8s: pod is not started")
+	}
+
+	return k.stats.get()
+}
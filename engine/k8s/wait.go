@@ -14,9 +14,14 @@ import (
 
 // Wait waits for the Job to complete and returns the container exit code as error if non-zero.
 func (k *k8s) Wait() error {
+	if k.stats != nil {
+		defer k.stats.stop()
+	}
+
 	ctx := context.Background()
 
 	var job *batchv1.Job
+	var failedReason string
 	err := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, 1*time.Hour, true, func(ctx context.Context) (bool, error) {
 		var err error
 		job, err = k.clientset.BatchV1().Jobs(k.jobNamespace).Get(ctx, k.jobName, metav1.GetOptions{})
@@ -28,6 +33,7 @@ func (k *k8s) Wait() error {
 				return true, nil
 			}
 			if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+				failedReason = c.Reason
 				return true, nil
 			}
 		}
@@ -39,9 +45,11 @@ func (k *k8s) Wait() error {
 
 	// Get exit code from the Pod's container status
 	exitCode := 0
+	var pod *corev1.Pod
 	pods, err := k.clientset.CoreV1().Pods(k.jobNamespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + k.jobName})
 	if err == nil && len(pods.Items) > 0 {
-		for _, c := range pods.Items[0].Status.ContainerStatuses {
+		pod = &pods.Items[0]
+		for _, c := range pod.Status.ContainerStatuses {
 			if c.Name == containerName && c.State.Terminated != nil {
 				exitCode = int(c.State.Terminated.ExitCode)
 				break
@@ -49,11 +57,46 @@ func (k *k8s) Wait() error {
 		}
 	}
 
+	reason := terminationReason(failedReason, pod)
+	// a failed job without an exit code did not exit by itself, e.g. an evicted pod
+	if failedReason != "" && exitCode == 0 {
+		exitCode = -1
+	}
+
 	if exitCode != 0 {
+		message := fmt.Sprintf("job %s exited with status %d", k.jobName, exitCode)
+		if reason != "" {
+			message = fmt.Sprintf("%s: %s", message, reason)
+		}
+
 		return &errors.ExitError{
-			Code:    exitCode,
-			Message: fmt.Sprintf("job %s exited with status %d", k.jobName, exitCode),
+			Code:      exitCode,
+			Message:   message,
+			OOMKilled: reason == errors.ReasonOOMKilled,
+			Reason:    reason,
 		}
 	}
 	return nil
 }
+
+// terminationReason returns why the pod of the job ended: the active deadline
+// of the job passed, the pod was evicted or the container was OOM killed.
+func terminationReason(failedReason string, pod *corev1.Pod) string {
+	if failedReason == "DeadlineExceeded" {
+		return errors.ReasonDeadlineExceeded
+	}
+	if pod == nil {
+		return ""
+	}
+
+	if pod.Status.Reason == "Evicted" {
+		return errors.ReasonEvicted
+	}
+	for _, c := range pod.Status.ContainerStatuses {
+		if c.Name == containerName && c.State.Terminated != nil && c.State.Terminated.Reason == "OOMKilled" {
+			return errors.ReasonOOMKilled
+		}
+	}
+
+	return ""
+}
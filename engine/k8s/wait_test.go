@@ -0,0 +1,33 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/go-zoox/command/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTerminationReason(t *testing.T) {
+	oomKilled := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  containerName,
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+	}}}}
+	evicted := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}}
+
+	for _, tc := range []struct {
+		failedReason string
+		pod          *corev1.Pod
+		expected     string
+	}{
+		{"", nil, ""},
+		{"", &corev1.Pod{}, ""},
+		{"BackoffLimitExceeded", oomKilled, errors.ReasonOOMKilled},
+		{"BackoffLimitExceeded", evicted, errors.ReasonEvicted},
+		{"DeadlineExceeded", oomKilled, errors.ReasonDeadlineExceeded},
+		{"DeadlineExceeded", nil, errors.ReasonDeadlineExceeded},
+	} {
+		if reason := terminationReason(tc.failedReason, tc.pod); reason != tc.expected {
+			t.Errorf("%s %+v: expected reason %q, got %q", tc.failedReason, tc.pod, tc.expected, reason)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package namespace
+
+// Cancel cancels the command.
+func (n *namespace) Cancel() error {
+	if n.cmd.Process == nil {
+		n.removeRoot()
+		return nil
+	}
+
+	// killing the init (pid 1 of the namespace) kills all its processes
+	return n.cmd.Process.Kill()
+}
@@ -0,0 +1,31 @@
+package namespace
+
+// Config is the configuration for the namespace engine.
+type Config struct {
+	Command     string
+	Environment map[string]string
+	// WorkDir is bind-mounted writable into the sandbox, default: a tmpfs /tmp
+	WorkDir string
+	Shell   string
+	// ReadOnly means none-interactive for terminal, which is used for show log, like top
+	ReadOnly bool
+
+	// DisableNetwork runs the command in a new network namespace with loopback only
+	DisableNetwork bool
+	// EgressAllow are the only hosts, networks and ports reachable, through a proxy
+	// listening on loopback inside the network namespace
+	EgressAllow []string
+	// Hostname is the hostname inside the UTS namespace, default: go-zoox
+	Hostname string
+	// Tmpfs are the tmpfs mounts, path to mount options, e.g. rw,noexec,nosuid,size=100m;
+	// the path has to exist on the host
+	Tmpfs map[string]string
+	// Seccomp is the seccomp profile: a preset name, an inline JSON profile or the path of a JSON profile
+	Seccomp string
+
+	// Custom Command Runner ID
+	ID string
+
+	// AllowedSystemEnvKeys is the allowed system environment keys, which will be inherited to the command
+	AllowedSystemEnvKeys []string
+}
@@ -0,0 +1,91 @@
+package namespace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/go-zoox/command/seccomp"
+	"golang.org/x/sys/unix"
+)
+
+// create prepares the re-executed init process in new namespaces.
+func (n *namespace) create() error {
+	env := []string{"TERM=xterm"}
+	for _, key := range n.cfg.AllowedSystemEnvKeys {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	for k, v := range n.cfg.Environment {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	var filter []unix.SockFilter
+	if n.cfg.Seccomp != "" {
+		profile, err := seccomp.Load(n.cfg.Seccomp)
+		if err != nil {
+			return fmt.Errorf("namespace: %w", err)
+		}
+
+		if filter, err = seccomp.Filter(profile); err != nil {
+			return fmt.Errorf("namespace: %w", err)
+		}
+	}
+
+	rootDir, err := os.MkdirTemp("", "go-zoox-command-namespace-")
+	if err != nil {
+		return fmt.Errorf("namespace: create root directory: %w", err)
+	}
+	n.rootDir = rootDir
+
+	// with an egress allowlist, the network is only reachable through the proxy
+	isEgressEnabled := len(n.cfg.EgressAllow) != 0
+	payload, err := json.Marshal(&initConfig{
+		Command:        n.cfg.Command,
+		Shell:          n.cfg.Shell,
+		WorkDir:        n.cfg.WorkDir,
+		Environment:    env,
+		Hostname:       n.cfg.Hostname,
+		Tmpfs:          n.cfg.Tmpfs,
+		Seccomp:        filter,
+		RootDir:        rootDir,
+		DisableNetwork: n.cfg.DisableNetwork || isEgressEnabled,
+		Egress:         isEgressEnabled,
+	})
+	if err != nil {
+		return err
+	}
+
+	n.cmd = exec.Command("/proc/self/exe")
+	n.cmd.Args[0] = initArg0
+	n.cmd.Env = []string{fmt.Sprintf("%s=%s", initEnvKey, payload)}
+
+	cloneflags := uintptr(syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWUTS | syscall.CLONE_NEWIPC)
+	if n.cfg.DisableNetwork || isEgressEnabled {
+		cloneflags |= syscall.CLONE_NEWNET
+	}
+
+	// the user keeps its id inside, so capabilities are only held by the init
+	// for mounting, which drops them all before it execs the shell, also for
+	// a root caller mapped to uid 0.
+	uid, gid := os.Getuid(), os.Getgid()
+	n.cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:                 cloneflags,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+
+	if isEgressEnabled {
+		if err := n.createEgress(); err != nil {
+			n.removeRoot()
+			return err
+		}
+	}
+
+	return nil
+}
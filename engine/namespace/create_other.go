@@ -0,0 +1,10 @@
+//go:build !linux
+
+package namespace
+
+import "fmt"
+
+// create fails as namespaces are only available on linux.
+func (n *namespace) create() error {
+	return fmt.Errorf("namespace: engine %s is only supported on linux", Name)
+}
@@ -0,0 +1,114 @@
+package namespace
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/go-zoox/command/egress"
+	"github.com/go-zoox/command/result"
+	"github.com/go-zoox/logger"
+	"golang.org/x/sys/unix"
+)
+
+// egressFd is the descriptor the init sends the listener of the proxy over.
+const egressFd = 3
+
+// createEgress prepares the egress proxy. The network namespace of the command
+// only has loopback, so the init listens on it and hands the listener to this
+// process, which serves the proxy and connects out from the host network.
+func (n *namespace) createEgress() error {
+	policy, err := egress.Parse(n.cfg.EgressAllow)
+	if err != nil {
+		return err
+	}
+
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("namespace: create egress socket: %w", err)
+	}
+
+	n.egressConn = os.NewFile(uintptr(fds[0]), "egress")
+	n.cmd.ExtraFiles = []*os.File{os.NewFile(uintptr(fds[1]), "egress-init")}
+	n.egressProxy = egress.NewProxy(policy, func(event *result.Event) {
+		logger.Warnf("namespace: egress: %s", event.Message)
+	})
+
+	return nil
+}
+
+// startEgress receives the listener from the started init and serves the proxy.
+// Nothing is received if the init fails, which is reported by Wait.
+func (n *namespace) startEgress() {
+	if n.egressConn == nil {
+		return
+	}
+	defer n.egressConn.Close()
+
+	// only the init holds the other end now, so a failed init means EOF
+	n.cmd.ExtraFiles[0].Close()
+
+	oob := make([]byte, unix.CmsgSpace(4))
+	_, oobn, _, _, err := unix.Recvmsg(int(n.egressConn.Fd()), make([]byte, 1), oob, 0)
+	if err != nil || oobn == 0 {
+		return
+	}
+
+	messages, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(messages) == 0 {
+		return
+	}
+	fds, err := unix.ParseUnixRights(&messages[0])
+	if err != nil || len(fds) == 0 {
+		return
+	}
+
+	file := os.NewFile(uintptr(fds[0]), "egress-listener")
+	defer file.Close()
+
+	listener, err := net.FileListener(file)
+	if err != nil {
+		logger.Warnf("namespace: egress: %s", err)
+		return
+	}
+
+	go n.egressProxy.Serve(listener)
+}
+
+// stopEgress stops the proxy.
+func (n *namespace) stopEgress() {
+	if n.egressProxy != nil {
+		n.egressProxy.Close()
+	}
+}
+
+// Events returns the connections denied by the egress proxy.
+func (n *namespace) Events() []result.Event {
+	if n.egressProxy == nil {
+		return nil
+	}
+
+	return n.egressProxy.Events()
+}
+
+// sendEgressListener listens on loopback inside the network namespace and
+// sends the listener to the engine, returning its address.
+func sendEgressListener() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("listen egress proxy: %w", err)
+	}
+	defer listener.Close()
+
+	file, err := listener.(*net.TCPListener).File()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if err := unix.Sendmsg(egressFd, []byte{0}, unix.UnixRights(int(file.Fd())), nil, 0); err != nil {
+		return "", fmt.Errorf("send egress listener: %w", err)
+	}
+
+	return listener.Addr().String(), unix.Close(egressFd)
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package namespace
+
+import "github.com/go-zoox/command/result"
+
+func (n *namespace) startEgress() {}
+
+func (n *namespace) stopEgress() {}
+
+// Events returns nothing as the engine is only supported on linux.
+func (n *namespace) Events() []result.Event {
+	return nil
+}
@@ -0,0 +1,358 @@
+package namespace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/go-zoox/command/egress"
+	"github.com/go-zoox/command/seccomp"
+	"golang.org/x/sys/unix"
+)
+
+// initEnvKey carries the init config to the re-executed process.
+const initEnvKey = "GO_ZOOX_COMMAND_NAMESPACE_INIT"
+
+// initArg0 is the process name of the init.
+const initArg0 = "go-zoox-command-namespace-init"
+
+// initExitCode is the exit code when the sandbox cannot be set up.
+const initExitCode = 125
+
+// initConfig is passed from the engine to the init process.
+type initConfig struct {
+	Command        string
+	Shell          string
+	WorkDir        string
+	Environment    []string
+	Hostname       string
+	Tmpfs          map[string]string
+	RootDir        string
+	DisableNetwork bool
+	// Egress sends a loopback listener for the egress proxy to the engine
+	Egress bool
+	// Seccomp is the filter installed right before the shell is executed
+	Seccomp []unix.SockFilter
+}
+
+func init() {
+	payload, ok := os.LookupEnv(initEnvKey)
+	if !ok {
+		return
+	}
+
+	// the seccomp filter is installed on this thread, which executes the shell
+	runtime.LockOSThread()
+
+	cfg := &initConfig{}
+	if err := json.Unmarshal([]byte(payload), cfg); err != nil {
+		fail(err)
+	}
+
+	if err := setup(cfg); err != nil {
+		fail(err)
+	}
+
+	if cfg.Egress {
+		address, err := sendEgressListener()
+		if err != nil {
+			fail(err)
+		}
+
+		cfg.Environment = egress.Environment(cfg.Environment, address)
+	}
+
+	args := []string{cfg.Shell}
+	if cfg.Command != "" {
+		args = append(args, "-c", cfg.Command)
+	}
+
+	if err := dropCapabilities(); err != nil {
+		fail(err)
+	}
+
+	if len(cfg.Seccomp) != 0 {
+		if err := seccomp.Install(cfg.Seccomp); err != nil {
+			fail(err)
+		}
+	}
+
+	fail(syscall.Exec(cfg.Shell, args, cfg.Environment))
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "namespace: %v\n", err)
+	os.Exit(initExitCode)
+}
+
+// setup builds the sandbox inside the new namespaces as the process holding
+// all capabilities of the user namespace.
+func setup(cfg *initConfig) error {
+	// keep the mounts inside the new mount namespace
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("make mounts private: %w", err)
+	}
+
+	root := cfg.RootDir
+	if err := unix.Mount("/", root, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return fmt.Errorf("bind root: %w", err)
+	}
+	if err := remountReadOnly(root); err != nil {
+		return err
+	}
+
+	if _, ok := cfg.Tmpfs["/tmp"]; !ok {
+		tmp := filepath.Join(root, "tmp")
+		if err := unix.Mount("tmpfs", tmp, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "mode=1777"); err != nil {
+			return fmt.Errorf("mount /tmp: %w", err)
+		}
+	}
+
+	for path, options := range cfg.Tmpfs {
+		flags, data := tmpfsOptions(options)
+		if err := unix.Mount("tmpfs", filepath.Join(root, filepath.Clean("/"+path)), "tmpfs", flags, data); err != nil {
+			return fmt.Errorf("mount tmpfs %s: %w", path, err)
+		}
+	}
+
+	if err := unix.Mount("proc", filepath.Join(root, "proc"), "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("mount /proc: %w", err)
+	}
+
+	workdir := "/tmp"
+	if cfg.WorkDir != "" {
+		workdir = cfg.WorkDir
+		target := filepath.Join(root, workdir)
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return fmt.Errorf("create workdir: %w", err)
+		}
+		if err := unix.Mount(workdir, target, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+			return fmt.Errorf("bind workdir: %w", err)
+		}
+	}
+
+	// pivot into the new root, stacking the old root on top and detaching it
+	if err := unix.Chdir(root); err != nil {
+		return err
+	}
+	if err := unix.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("pivot root: %w", err)
+	}
+	if err := unix.Unmount(".", unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("detach old root: %w", err)
+	}
+	if err := unix.Chdir(workdir); err != nil {
+		return fmt.Errorf("enter workdir: %w", err)
+	}
+
+	if err := unix.Sethostname([]byte(cfg.Hostname)); err != nil {
+		return fmt.Errorf("set hostname: %w", err)
+	}
+
+	if cfg.DisableNetwork {
+		if err := loopbackUp(); err != nil {
+			return fmt.Errorf("bring up loopback: %w", err)
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("set no_new_privs: %w", err)
+	}
+
+	return nil
+}
+
+// dropCapabilities drops all capabilities, including the bounding set, so the
+// shell does not get them back on exec when the caller is root, which is
+// mapped to itself, e.g. to remount the root read-write.
+func dropCapabilities() error {
+	for capability := 0; capability <= unix.CAP_LAST_CAP; capability++ {
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(capability), 0, 0, 0); err != nil && err != unix.EINVAL {
+			return fmt.Errorf("drop capability %d from the bounding set: %w", capability, err)
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0); err != nil {
+		return fmt.Errorf("clear ambient capabilities: %w", err)
+	}
+
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	data := [2]unix.CapUserData{}
+	if err := unix.Capset(&header, &data[0]); err != nil {
+		return fmt.Errorf("drop capabilities: %w", err)
+	}
+
+	return nil
+}
+
+// tmpfsOptions splits the mount options of a tmpfs into flags and data,
+// defaulting to nosuid, nodev and mode 1777.
+func tmpfsOptions(options string) (uintptr, string) {
+	flags := uintptr(unix.MS_NOSUID | unix.MS_NODEV)
+	data := []string{}
+	isModeSet := false
+	for _, option := range strings.Split(options, ",") {
+		switch option {
+		case "", "rw":
+		case "ro":
+			flags |= unix.MS_RDONLY
+		case "noexec":
+			flags |= unix.MS_NOEXEC
+		case "nosuid", "nodev":
+		case "noatime":
+			flags |= unix.MS_NOATIME
+		default:
+			if strings.HasPrefix(option, "mode=") {
+				isModeSet = true
+			}
+			data = append(data, option)
+		}
+	}
+	if !isModeSet {
+		data = append(data, "mode=1777")
+	}
+
+	return flags, strings.Join(data, ",")
+}
+
+// remountReadOnly remounts the bind of root and all its submounts read-only.
+// Flags locked by the user namespace, e.g. nosuid, have to be kept. A
+// submount that cannot be made read-only is detached, so no writable mount
+// stays reachable below root.
+func remountReadOnly(root string) error {
+	mounts, err := mountPoints(root)
+	if err != nil {
+		return err
+	}
+
+	detached := []string{}
+	for _, mountpoint := range mounts {
+		if isBelow(mountpoint, detached) {
+			continue
+		}
+
+		var st unix.Statfs_t
+		if err := unix.Statfs(mountpoint, &st); err != nil {
+			return fmt.Errorf("remount %s read-only: %w", mountpoint, err)
+		}
+
+		flags := uintptr(unix.MS_BIND | unix.MS_REMOUNT | unix.MS_RDONLY)
+		for st_flag, ms_flag := range map[int64]uintptr{
+			unix.ST_NOSUID:     unix.MS_NOSUID,
+			unix.ST_NODEV:      unix.MS_NODEV,
+			unix.ST_NOEXEC:     unix.MS_NOEXEC,
+			unix.ST_NOATIME:    unix.MS_NOATIME,
+			unix.ST_NODIRATIME: unix.MS_NODIRATIME,
+			unix.ST_RELATIME:   unix.MS_RELATIME,
+		} {
+			if int64(st.Flags)&st_flag != 0 {
+				flags |= ms_flag
+			}
+		}
+
+		if err := unix.Mount("", mountpoint, "", flags, ""); err != nil {
+			if mountpoint == root {
+				return fmt.Errorf("remount root read-only: %w", err)
+			}
+
+			if uerr := unix.Unmount(mountpoint, unix.MNT_DETACH); uerr != nil {
+				return fmt.Errorf("remount %s read-only: %w (unmount: %v)", mountpoint, err, uerr)
+			}
+
+			detached = append(detached, mountpoint)
+		}
+	}
+
+	return nil
+}
+
+// isBelow reports whether path is one of dirs or lies below one of them.
+func isBelow(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, dir+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mountPoints returns the mount points at or below root, parents first.
+func mountPoints(root string) ([]string, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mounts := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		mountpoint := unescapeMountPoint(fields[4])
+		if mountpoint == root || strings.HasPrefix(mountpoint, root+"/") {
+			mounts = append(mounts, mountpoint)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(mounts, func(i, j int) bool {
+		return len(mounts[i]) < len(mounts[j])
+	})
+
+	return mounts, nil
+}
+
+// unescapeMountPoint decodes the octal escapes (e.g. \040 for space) of mountinfo.
+func unescapeMountPoint(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			var c byte
+			if _, err := fmt.Sscanf(s[i+1:i+4], "%03o", &c); err == nil {
+				b.WriteByte(c)
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// loopbackUp brings up the loopback interface of the new network namespace.
+func loopbackUp() error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	ifreq, err := unix.NewIfreq("lo")
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifreq); err != nil {
+		return err
+	}
+
+	ifreq.SetUint16(ifreq.Uint16() | unix.IFF_UP)
+	return unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifreq)
+}
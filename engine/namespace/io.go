@@ -0,0 +1,21 @@
+package namespace
+
+import "io"
+
+// SetStdin sets the stdin for the command.
+func (n *namespace) SetStdin(stdin io.Reader) error {
+	n.stdin = stdin
+	return nil
+}
+
+// SetStdout sets the stdout for the command.
+func (n *namespace) SetStdout(stdout io.Writer) error {
+	n.stdout = stdout
+	return nil
+}
+
+// SetStderr sets the stderr for the command.
+func (n *namespace) SetStderr(stderr io.Writer) error {
+	n.stderr = stderr
+	return nil
+}
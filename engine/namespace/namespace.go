@@ -0,0 +1,55 @@
+// Package namespace implements an engine running commands in linux namespaces
+// (user, mount, pid, uts, ipc and optionally network) with a read-only root,
+// a tmpfs /tmp and an isolated workdir, without a container runtime.
+package namespace
+
+import (
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/go-zoox/command/egress"
+	"github.com/go-zoox/command/engine"
+)
+
+// Name is the name of the engine.
+const Name = "namespace"
+
+type namespace struct {
+	cfg *Config
+	//
+	cmd *exec.Cmd
+	// rootDir is the empty host directory the new root is mounted on
+	rootDir string
+	// egressConn receives the listener of the egress proxy from the init
+	egressConn  *os.File
+	egressProxy *egress.Proxy
+	//
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// New creates a new namespace engine.
+func New(cfg *Config) (engine.Engine, error) {
+	if cfg.Shell == "" {
+		cfg.Shell = "/bin/sh"
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname = "go-zoox"
+	}
+
+	n := &namespace{
+		cfg: cfg,
+		//
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+
+	if err := n.create(); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
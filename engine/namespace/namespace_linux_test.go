@@ -0,0 +1,144 @@
+package namespace
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// run runs the command in the namespace engine, skipping the test when user
+// namespaces are not available.
+func run(t *testing.T, cfg *Config) (string, error) {
+	t.Helper()
+
+	eng, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	buf := &strings.Builder{}
+	eng.SetStdout(buf)
+	eng.SetStderr(buf)
+	if err := eng.Start(); err != nil {
+		t.Skipf("user namespaces unavailable: %v", err)
+	}
+
+	err = eng.Wait()
+	if strings.HasPrefix(buf.String(), "namespace: ") {
+		t.Skipf("namespace setup unavailable: %s", buf.String())
+	}
+
+	return buf.String(), err
+}
+
+func TestNamespace_Isolation(t *testing.T) {
+	out, err := run(t, &Config{
+		Command:        "hostname; echo $$; cat /proc/net/dev | grep -c :",
+		DisableNetwork: true,
+	})
+	if err != nil {
+		t.Fatalf("Wait: %v: %s", err, out)
+	}
+
+	if got := strings.Fields(out); len(got) != 3 || got[0] != "go-zoox" || got[1] != "1" || got[2] != "1" {
+		t.Errorf("expected hostname go-zoox, pid 1 and loopback only, got %q", out)
+	}
+}
+
+func TestNamespace_Filesystem(t *testing.T) {
+	workdir := t.TempDir()
+
+	out, err := run(t, &Config{
+		Command: "touch /etc/go-zoox 2>/dev/null && echo root-writable; echo tmp > /tmp/file && cat /tmp/file; pwd; echo out > result",
+		WorkDir: workdir,
+	})
+	if err != nil {
+		t.Fatalf("Wait: %v: %s", err, out)
+	}
+
+	if got := strings.Fields(out); len(got) != 2 || got[0] != "tmp" || got[1] != workdir {
+		t.Errorf("expected read-only root, writable /tmp and workdir %s, got %q", workdir, out)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(workdir, "result")); err != nil || string(data) != "out\n" {
+		t.Errorf("expected result in workdir, got %q (%v)", data, err)
+	}
+}
+
+func TestNamespace_ExitCode(t *testing.T) {
+	out, err := run(t, &Config{
+		Command: "exit 3",
+	})
+	if err == nil {
+		t.Fatalf("expected exit error, got output %q", out)
+	}
+	if !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("expected exit status 3, got %v", err)
+	}
+}
+
+func TestNamespace_Egress(t *testing.T) {
+	if _, err := exec.LookPath("curl"); err != nil {
+		t.Skip("curl not found")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "allowed")
+	}))
+	defer server.Close()
+
+	denied := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "denied")
+	}))
+	defer denied.Close()
+
+	// the servers listen on the host loopback, which NO_PROXY excludes from the proxy
+	cfg := &Config{
+		Command:     fmt.Sprintf("curl -s --noproxy '' %s; echo; curl -s --noproxy '' -o /dev/null -w '%%{http_code}' %s", server.URL, denied.URL),
+		Environment: map[string]string{"PATH": os.Getenv("PATH")},
+		EgressAllow: []string{strings.TrimPrefix(server.URL, "http://")},
+	}
+	out, err := run(t, cfg)
+	if err != nil {
+		t.Fatalf("Wait: %v: %s", err, out)
+	}
+
+	if got := strings.Fields(out); len(got) != 2 || got[0] != "allowed" || got[1] != "403" {
+		t.Errorf("expected the allowed server to answer and the other to be denied, got %q", out)
+	}
+}
+
+func TestNamespace_ProfileSettings(t *testing.T) {
+	out, err := run(t, &Config{
+		Command:  "hostname; echo tmpfs > /var/tmp/file && cat /var/tmp/file; mkdir /tmp/dir 2>/dev/null || echo denied",
+		Hostname: "sandbox",
+		Tmpfs:    map[string]string{"/var/tmp": "rw,noexec,size=1m"},
+		Seccomp:  `{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [{"names": ["mkdir", "mkdirat"], "action": "SCMP_ACT_ERRNO"}]}`,
+	})
+	if err != nil {
+		t.Fatalf("Wait: %v: %s", err, out)
+	}
+
+	if got := strings.Fields(out); len(got) != 3 || got[0] != "sandbox" || got[1] != "tmpfs" || got[2] != "denied" {
+		t.Errorf("expected hostname, writable tmpfs and mkdir denied, got %q", out)
+	}
+}
+
+func TestNamespace_RootCannotRemount(t *testing.T) {
+
+	out, err := run(t, &Config{
+		Command: "mount -o remount,rw,bind / 2>/dev/null && echo remounted; touch /etc/go-zoox 2>/dev/null && echo root-writable; grep CapEff /proc/self/status",
+	})
+	if err != nil {
+		t.Fatalf("Wait: %v: %s", err, out)
+	}
+
+	if got := strings.Fields(out); len(got) != 2 || got[0] != "CapEff:" || got[1] != "0000000000000000" {
+		t.Errorf("expected no capabilities and a read-only root, got %q", out)
+	}
+}
@@ -0,0 +1,18 @@
+package namespace
+
+// Start starts the command.
+func (n *namespace) Start() error {
+	n.cmd.Stdin = n.stdin
+	n.cmd.Stdout = n.stdout
+	n.cmd.Stderr = n.stderr
+
+	if err := n.cmd.Start(); err != nil {
+		n.removeRoot()
+		n.stopEgress()
+		return err
+	}
+
+	n.startEgress()
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package namespace
+
+import (
+	"github.com/creack/pty"
+	"github.com/go-zoox/command/engine/host"
+	"github.com/go-zoox/command/terminal"
+)
+
+// Terminal returns a terminal for the command.
+func (n *namespace) Terminal() (terminal.Terminal, error) {
+	// keep the namespace attributes, pty only adds the session and tty
+	attrs := *n.cmd.SysProcAttr
+	attrs.Setsid = true
+	attrs.Setctty = true
+
+	t, err := pty.StartWithAttrs(n.cmd, nil, &attrs)
+	if err != nil {
+		n.removeRoot()
+		n.stopEgress()
+		return nil, err
+	}
+
+	n.startEgress()
+
+	return &namespaceTerminal{
+		Terminal: &host.Terminal{
+			File:     t,
+			Cmd:      n.cmd,
+			ReadOnly: n.cfg.ReadOnly,
+		},
+		n: n,
+	}, nil
+}
+
+// namespaceTerminal stops the egress proxy once the command exits.
+type namespaceTerminal struct {
+	*host.Terminal
+	n *namespace
+}
+
+// Wait waits for the command to finish.
+func (t *namespaceTerminal) Wait() error {
+	defer t.n.stopEgress()
+
+	return t.Terminal.Wait()
+}
+
+// Close closes the terminal and kills the command.
+func (t *namespaceTerminal) Close() error {
+	defer t.n.stopEgress()
+
+	return t.Terminal.Close()
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/go-zoox/command/terminal"
+)
+
+// Terminal returns a terminal for the command.
+func (n *namespace) Terminal() (terminal.Terminal, error) {
+	return nil, fmt.Errorf("namespace: engine %s is only supported on linux", Name)
+}
@@ -0,0 +1,46 @@
+package namespace
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/go-zoox/command/errors"
+)
+
+// Wait waits for the command to finish.
+func (n *namespace) Wait() error {
+	defer n.removeRoot()
+	defer n.stopEgress()
+
+	if err := n.cmd.Wait(); err != nil {
+		v, ok := err.(*exec.ExitError)
+		if !ok {
+			return &errors.ExitError{
+				Code:    1,
+				Message: err.Error(),
+			}
+		}
+
+		exitErr := &errors.ExitError{
+			Code:    v.ExitCode(),
+			Message: v.Error(),
+		}
+		if status, ok := v.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			exitErr.Reason = errors.ReasonSignal
+			exitErr.Signal = errors.SignalName(int(status.Signal()))
+		}
+
+		return exitErr
+	}
+
+	return nil
+}
+
+// removeRoot removes the mount point of the new root, which is empty on the
+// host because the mounts only exist in the namespace of the command.
+func (n *namespace) removeRoot() {
+	if n.rootDir != "" {
+		os.Remove(n.rootDir)
+	}
+}
@@ -8,7 +8,12 @@ import (
 
 // Cancel cancels the command.
 func (p *podman) Cancel() error {
+	defer p.egress.Remove(p.container.ID)
+	defer p.services.Remove(p.container.ID)
+	defer p.oom.Stop()
+
 	return p.client.ContainerRemove(context.Background(), p.container.ID, container.RemoveOptions{
-		Force: true,
+		Force:         true,
+		RemoveVolumes: true,
 	})
 }
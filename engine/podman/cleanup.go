@@ -0,0 +1,15 @@
+package podman
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Cleanup removes the container kept after exit.
+func (p *podman) Cleanup() error {
+	return p.client.ContainerRemove(context.Background(), p.container.ID, container.RemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	})
+}
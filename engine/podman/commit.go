@@ -0,0 +1,20 @@
+package podman
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Commit creates the image ref from the container kept after exit and returns the image ID.
+func (p *podman) Commit(ctx context.Context, ref string) (string, error) {
+	resp, err := p.client.ContainerCommit(ctx, p.container.ID, container.CommitOptions{
+		Reference: ref,
+	})
+	if err != nil {
+		return "", fmt.Errorf("podman: commit container: %w", err)
+	}
+
+	return resp.ID, nil
+}
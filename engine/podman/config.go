@@ -1,5 +1,7 @@
 package podman
 
+import "github.com/go-zoox/command/config"
+
 // Config is the configuration for the podman engine.
 type Config struct {
 	Command     string
@@ -8,14 +10,72 @@ type Config struct {
 	User        string
 	Shell       string
 	ReadOnly    bool
+	// TTY allocates a pseudo-TTY for the container, which merges stderr into stdout
+	TTY bool
+
+	// Script is staged into the container and run by Interpreter instead of Command
+	Script      string
+	Interpreter string
 
 	Image          string
 	Memory         int64
 	CPU            float64
+	PidsLimit      int64
 	Platform       string
 	Network        string
 	DisableNetwork bool
 	Privileged     bool
+	// MemorySwap is the limit of memory plus swap, unit: MB, -1 means unlimited swap
+	MemorySwap int64
+	// MemoryReservation is the soft memory limit, unit: MB
+	MemoryReservation int64
+	// CPUSet are the CPUs the container can run on, e.g. 0-3 or 0,2
+	CPUSet string
+	// ShmSize is the size of /dev/shm, unit: MB
+	ShmSize int64
+	// Ulimits are the resource limits of the processes in the container
+	Ulimits []config.Ulimit
+	// BlkioWeight is the relative block I/O weight, from 10 to 1000
+	BlkioWeight uint16
+	// StorageSize is the size limit of the writable layer, unit: MB
+	StorageSize int64
+	// Networks are the additional networks the container is connected to
+	Networks []string
+	// NetworkAliases are the aliases of the container on its networks
+	NetworkAliases []string
+	// Hostname is the hostname of the container, default: go-zoox
+	Hostname string
+	// Ports are the published ports as [ip:][hostPort:]containerPort[/protocol]
+	Ports []string
+	// DNS are the DNS servers of the container
+	DNS []string
+	// ExtraHosts are the additional /etc/hosts entries of the container as host:ip
+	ExtraHosts []string
+	// Mounts are the bind mounts, volumes and tmpfs mounts of the container
+	Mounts []config.Mount
+	// Services are the containers started on a private network before the command, and removed after it
+	Services []config.Service
+	// EgressAllow are the only hosts, networks and ports reachable through the egress proxy
+	EgressAllow []string
+	// Sandbox enables strict security settings for untrusted code
+	Sandbox bool
+	// SandboxProfile is the profile of sandbox mode, default: standard
+	SandboxProfile *config.SandboxProfile
+	// Seccomp is the seccomp profile: a preset (default, strict, no-network), a Docker-format JSON profile or its path
+	Seccomp string
+
+	// PullPolicy is the image pull policy: Always, IfNotPresent or Never, default: IfNotPresent
+	PullPolicy string
+	// Build builds the image of the command before it runs, instead of Image
+	Build *config.Build
+	// RegistryAuths are the credentials of the image registries, looked up before ~/.docker/config.json
+	RegistryAuths []config.RegistryAuth
+	// ImageRegistry is the image registry address of the credential below, looked up after RegistryAuths
+	ImageRegistry string
+	// ImageRegistryUsername is the image registry username
+	ImageRegistryUsername string
+	// ImageRegistryPassword is the image registry password
+	ImageRegistryPassword string
 
 	// PodmanHost is the Podman socket (Docker-compatible API). Default: unix:///run/podman/podman.sock
 	PodmanHost string
@@ -23,4 +83,7 @@ type Config struct {
 	ID string
 
 	AllowedSystemEnvKeys []string
+
+	// IsAutoRemoveDisabled keeps the container after exit until Cleanup is called
+	IsAutoRemoveDisabled bool
 }
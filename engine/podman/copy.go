@@ -0,0 +1,18 @@
+package podman
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+)
+
+// CopyTo extracts content into the directory dst of the command container.
+func (p *podman) CopyTo(ctx context.Context, dst string, content io.Reader) error {
+	return dockerapi.CopyTo(ctx, p.client, p.container.ID, Name, dst, content)
+}
+
+// CopyFrom archives the file or directory src of the command container.
+func (p *podman) CopyFrom(ctx context.Context, src string) (io.ReadCloser, error) {
+	return dockerapi.CopyFrom(ctx, p.client, p.container.ID, Name, src)
+}
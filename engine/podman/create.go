@@ -3,18 +3,42 @@ package podman
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+	"github.com/go-zoox/command/pull"
+	"github.com/go-zoox/command/sandbox"
+	"github.com/go-zoox/command/seccomp"
 	"github.com/go-zoox/core-utils/cast"
+	"github.com/go-zoox/logger"
 )
 
 // defaultPodmanHost is the default Podman socket (Docker-compatible API).
 const defaultPodmanHost = "unix:///run/podman/podman.sock"
 
+// logOutput is where the progress of the engine is logged, apart from the
+// output of the command.
+var logOutput io.Writer = os.Stderr
+
 // create creates a container via Podman's Docker-compatible API.
 func (p *podman) create() (err error) {
+	defer func() {
+		if err != nil {
+			p.services.Remove(p.container.ID)
+			p.egress.Remove(p.container.ID)
+		}
+	}()
+
+	if p.cfg.Script != "" {
+		p.cfg.Command = p.scriptCommand()
+	}
+
 	if p.cfg.Command != "" {
 		p.args = append(p.args, "-c", p.cfg.Command)
 	}
@@ -45,13 +69,12 @@ func (p *podman) create() (err error) {
 	}
 
 	cfg := &container.Config{
-		Hostname:     "go-zoox",
 		Image:        p.cfg.Image,
 		Cmd:          append([]string{p.cfg.Shell}, p.args...),
 		User:         p.cfg.User,
 		WorkingDir:   p.cfg.WorkDir,
 		Env:          p.env,
-		Tty:          true,
+		Tty:          p.cfg.TTY,
 		OpenStdin:    true,
 		AttachStdin:  true,
 		AttachStdout: true,
@@ -60,11 +83,48 @@ func (p *podman) create() (err error) {
 	}
 
 	hostCfg := &container.HostConfig{
-		AutoRemove: true,
+		AutoRemove: !p.cfg.IsAutoRemoveDisabled,
 		Resources:  container.Resources{},
 		Privileged: p.cfg.Privileged,
 	}
 
+	// Apply sandbox security settings
+	if p.cfg.Sandbox {
+		profile := p.cfg.SandboxProfile
+		if profile == nil {
+			if profile, err = sandbox.Get(sandbox.Standard); err != nil {
+				return err
+			}
+		}
+
+		hostCfg.Privileged = false
+		hostCfg.SecurityOpt = []string{
+			"no-new-privileges:true",
+		}
+		hostCfg.CapDrop = []string{
+			"ALL",
+		}
+		hostCfg.CapAdd = profile.Capabilities
+		hostCfg.ReadonlyRootfs = profile.ReadOnlyRootfs
+		hostCfg.Tmpfs = profile.Tmpfs
+
+		if profile.Network != sandbox.NetworkAllow && p.cfg.Network == "" && len(p.cfg.Networks) == 0 {
+			hostCfg.NetworkMode = "none"
+		}
+	}
+
+	if p.cfg.Seccomp != "" {
+		opt, err := seccomp.SecurityOpt(p.cfg.Seccomp)
+		if err != nil {
+			return fmt.Errorf("podman: %w", err)
+		}
+
+		// the default preset is applied by podman itself
+		if opt != "" {
+			hostCfg.SecurityOpt = append(hostCfg.SecurityOpt, opt)
+		}
+	}
+
 	if p.cfg.Memory != 0 {
 		hostCfg.Resources.Memory = p.cfg.Memory * 1024 * 1024
 	}
@@ -75,11 +135,130 @@ func (p *podman) create() (err error) {
 	if p.cfg.DisableNetwork {
 		hostCfg.NetworkMode = "none"
 	}
+	if p.cfg.PidsLimit != 0 {
+		hostCfg.Resources.PidsLimit = &p.cfg.PidsLimit
+	}
+	p.applyResources(hostCfg)
+	if p.cfg.Script != "" {
+		hostCfg.Mounts = append(hostCfg.Mounts, scriptMount())
+	}
+	hostCfg.Mounts = append(hostCfg.Mounts, dockerapi.Mounts(p.cfg.Mounts)...)
+
+	networkCfg := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{},
+	}
+	if err := dockerapi.ApplyNetwork(p.client, p.network(), Name, logOutput, cfg, hostCfg, networkCfg); err != nil {
+		return err
+	}
+
+	if len(p.cfg.EgressAllow) != 0 {
+		p.egress = dockerapi.NewEgress(p.client, p.cfg.ID, p.cfg.EgressAllow, Name, logOutput)
+		if err := p.egress.Create(cfg, hostCfg, networkCfg); err != nil {
+			return err
+		}
+	}
+
+	p.registryAuths = append(p.registryAuths, p.cfg.RegistryAuths...)
+	if auth := dockerapi.ImageRegistryAuth(p.cfg.ImageRegistry, p.cfg.ImageRegistryUsername, p.cfg.ImageRegistryPassword); auth != nil {
+		p.registryAuths = append(p.registryAuths, *auth)
+	}
 
-	p.container, err = p.client.ContainerCreate(context.Background(), cfg, hostCfg, nil, nil, p.cfg.ID)
+	if p.cfg.Build != nil {
+		if p.cfg.Image, err = dockerapi.BuildImage(p.client, p.cfg.Build, p.cfg.Platform, p.cfg.PullPolicy, p.registryAuths, Name, logOutput); err != nil {
+			return err
+		}
+		cfg.Image = p.cfg.Image
+	} else if err := p.pullImage(p.cfg.Image); err != nil {
+		return err
+	}
+
+	if len(p.cfg.Services) != 0 {
+		p.services = dockerapi.NewServices(p.client, p.cfg.ID, p.cfg.Services, p.pullImage, Name, logOutput)
+		if err := p.services.Create(hostCfg, networkCfg); err != nil {
+			return err
+		}
+	}
+
+	p.containerCfg, p.hostCfg, p.networkCfg = cfg, hostCfg, networkCfg
+	return p.createContainer()
+}
+
+// createContainer creates the container from the prepared configs, attached to
+// its networks and services, with the script staged.
+func (p *podman) createContainer() (err error) {
+	p.container, err = p.client.ContainerCreate(context.Background(), p.containerCfg, p.hostCfg, p.networkCfg, nil, p.cfg.ID)
 	if err != nil {
 		return fmt.Errorf("podman: create container: %w", err)
 	}
 
+	if err := dockerapi.ConnectNetworks(p.client, p.network(), p.container.ID, Name, logOutput); err != nil {
+		p.client.ContainerRemove(context.Background(), p.container.ID, container.RemoveOptions{Force: true})
+		return err
+	}
+
+	if err := p.services.Connect(p.hostCfg, p.container.ID); err != nil {
+		p.client.ContainerRemove(context.Background(), p.container.ID, container.RemoveOptions{Force: true})
+		return err
+	}
+
+	if p.cfg.Script != "" {
+		if err := p.stageScript(); err != nil {
+			p.client.ContainerRemove(context.Background(), p.container.ID, container.RemoveOptions{Force: true})
+			return err
+		}
+	}
+
+	return nil
+}
+
+// allocateTTY recreates the container with a pseudo-TTY, which a terminal requires,
+// if it was created without one.
+func (p *podman) allocateTTY() error {
+	if p.containerCfg.Tty {
+		return nil
+	}
+
+	if err := p.client.ContainerRemove(context.Background(), p.container.ID, container.RemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	}); err != nil {
+		return fmt.Errorf("podman: remove container: %w", err)
+	}
+
+	p.containerCfg.Tty = true
+	return p.createContainer()
+}
+
+// pullImage pulls the image as required by the pull policy, with the credential of its registry.
+func (p *podman) pullImage(ref string) error {
+	_, _, err := p.client.ImageInspectWithRaw(context.Background(), ref)
+	required, err := pull.IsRequired(p.cfg.PullPolicy, err == nil)
+	if err != nil {
+		return fmt.Errorf("podman: %w: %s", err, ref)
+	}
+	if !required {
+		return nil
+	}
+
+	auth, err := pull.Auth(ref, p.registryAuths)
+	if err != nil {
+		return fmt.Errorf("podman: %w", err)
+	}
+
+	logger.Infof("podman: pull image %s ...", ref)
+	reader, err := p.client.ImagePull(context.Background(), ref, image.PullOptions{
+		Platform:     p.cfg.Platform,
+		RegistryAuth: auth,
+	})
+	if err != nil {
+		return fmt.Errorf("podman: pull image %s: %w", ref, err)
+	}
+	defer reader.Close()
+
+	// the stream reports the errors of the pull
+	if err := jsonmessage.DisplayJSONMessagesStream(reader, io.Discard, 0, false, nil); err != nil {
+		return fmt.Errorf("podman: pull image %s: %w", ref, err)
+	}
+
 	return nil
 }
@@ -0,0 +1,14 @@
+package podman
+
+import (
+	"context"
+
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+	"github.com/go-zoox/command/result"
+)
+
+// Diff returns the changes of the container layer since the container was
+// created. Bind mounts, e.g. WorkDir, are not part of the layer.
+func (p *podman) Diff(ctx context.Context) ([]result.Change, error) {
+	return dockerapi.Diff(ctx, p.client, p.container.ID)
+}
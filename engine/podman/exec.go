@@ -0,0 +1,14 @@
+package podman
+
+import (
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+)
+
+// ExecTerminal is a terminal for a process executed inside a container.
+type ExecTerminal = dockerapi.ExecTerminal
+
+// Exec creates a process running inside the command container.
+func (p *podman) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	return dockerapi.NewProcess(p.client, p.container.ID, Name, cfg), nil
+}
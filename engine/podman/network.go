@@ -0,0 +1,29 @@
+package podman
+
+import (
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+	"github.com/go-zoox/command/result"
+)
+
+// network returns the network configuration of the container.
+func (p *podman) network() *dockerapi.Network {
+	return &dockerapi.Network{
+		Hostname:       p.cfg.Hostname,
+		Ports:          p.cfg.Ports,
+		DNS:            p.cfg.DNS,
+		ExtraHosts:     p.cfg.ExtraHosts,
+		Network:        p.cfg.Network,
+		Networks:       p.cfg.Networks,
+		NetworkAliases: p.cfg.NetworkAliases,
+	}
+}
+
+// Ports returns the published ports with their bound host ports, once started.
+func (p *podman) Ports() []result.Port {
+	return p.ports
+}
+
+// Events returns the connections denied by the egress proxy.
+func (p *podman) Events() []result.Event {
+	return p.egress.Events()
+}
@@ -0,0 +1,8 @@
+package podman
+
+import "github.com/go-zoox/command/engine/internal/dockerapi"
+
+// isOOMKilled reports whether the OOM killer killed a process of the container.
+func (p *podman) isOOMKilled() bool {
+	return dockerapi.IsOOMKilled(p.client, p.container.ID, p.cfg.IsAutoRemoveDisabled, p.oom)
+}
@@ -0,0 +1,19 @@
+package podman
+
+import (
+	"github.com/docker/docker/api/types/container"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+)
+
+// applyResources applies the resource controls beyond memory, CPU and pids to the container.
+func (p *podman) applyResources(hostCfg *container.HostConfig) {
+	dockerapi.ApplyResources(hostCfg, &dockerapi.Resources{
+		MemorySwap:        p.cfg.MemorySwap,
+		MemoryReservation: p.cfg.MemoryReservation,
+		CPUSet:            p.cfg.CPUSet,
+		ShmSize:           p.cfg.ShmSize,
+		Ulimits:           p.cfg.Ulimits,
+		BlkioWeight:       p.cfg.BlkioWeight,
+		StorageSize:       p.cfg.StorageSize,
+	})
+}
@@ -0,0 +1,28 @@
+package podman
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+)
+
+// NewSession creates a new podman session, which keeps one container running
+// and executes every command inside it with the exec API.
+func NewSession(cfg *Config) (engine.Session, error) {
+	cfg.Command = dockerapi.SessionKeepAliveCommand
+
+	e, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	p := e.(*podman)
+	if err := p.client.ContainerStart(context.Background(), p.container.ID, container.StartOptions{}); err != nil {
+		p.Cancel()
+		return nil, err
+	}
+
+	return dockerapi.NewSession(p.client, p.container.ID, Name, p.Cancel), nil
+}
@@ -5,7 +5,11 @@ import (
 	"io"
 	"net"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/go-zoox/command/engine/internal/dockerapi"
+	"github.com/go-zoox/logger"
 )
 
 // Start starts the command.
@@ -20,17 +24,37 @@ func (p *podman) Start() error {
 		return err
 	}
 
-	if err := applyStdin(stream.Conn, p.stdin); err != nil {
-		return err
+	if p.containerCfg.Tty {
+		if err := applyStdin(stream.Conn, p.stdin); err != nil {
+			return err
+		}
+		if err := applyStdout(stream.Conn, p.stdout); err != nil {
+			return err
+		}
+		if err := applyStderr(stream.Conn, p.stderr); err != nil {
+			return err
+		}
+	} else {
+		p.output = demux(stream, p.stdin, p.stdout, p.stderr)
 	}
-	if err := applyStdout(stream.Conn, p.stdout); err != nil {
+
+	p.oom = dockerapi.WatchOOM(p.client, p.container.ID)
+	p.waitC, p.waitErrC = p.client.ContainerWait(context.Background(), p.container.ID, p.waitCondition())
+
+	if err := p.client.ContainerStart(context.Background(), p.container.ID, container.StartOptions{}); err != nil {
 		return err
 	}
-	if err := applyStderr(stream.Conn, p.stderr); err != nil {
-		return err
+
+	p.stats = dockerapi.SampleStats(p.client, p.container.ID, Name)
+
+	if len(p.cfg.Ports) != 0 {
+		var err error
+		if p.ports, err = dockerapi.InspectPorts(p.client, p.container.ID, Name); err != nil {
+			logger.Warnf("%s", err)
+		}
 	}
 
-	return p.client.ContainerStart(context.Background(), p.container.ID, container.StartOptions{})
+	return nil
 }
 
 func applyStdin(conn net.Conn, stdin io.Reader) error {
@@ -50,3 +74,31 @@ func applyStdout(conn net.Conn, stdout io.Writer) error {
 func applyStderr(conn net.Conn, stderr io.Writer) error {
 	return nil
 }
+
+// demux copies stdin to a container without TTY, closing its stdin at EOF, and
+// demultiplexes its output into stdout and stderr. The channel is closed when the
+// output ends.
+func demux(stream types.HijackedResponse, stdin io.Reader, stdout, stderr io.Writer) chan struct{} {
+	if stdin != nil {
+		go func() {
+			io.Copy(stream.Conn, stdin)
+			stream.CloseWrite()
+		}()
+	} else {
+		stream.CloseWrite()
+	}
+
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stdcopy.StdCopy(stdout, stderr, stream.Reader)
+	}()
+	return done
+}
@@ -0,0 +1,16 @@
+package podman
+
+import (
+	"errors"
+
+	"github.com/go-zoox/command/result"
+)
+
+// Stats returns the resource usage of the container from the podman stats stream.
+func (p *podman) Stats() (*result.Stats, error) {
+	if p.stats == nil {
+		return nil, errors.New("container is not started")
+	}
+
+	return p.stats.Get()
+}
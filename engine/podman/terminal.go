@@ -15,6 +15,10 @@ import (
 
 // Terminal returns a terminal.
 func (p *podman) Terminal() (terminal.Terminal, error) {
+	if err := p.allocateTTY(); err != nil {
+		return nil, err
+	}
+
 	stream, err := p.client.ContainerAttach(context.Background(), p.container.ID, container.AttachOptions{
 		Stream: true,
 		Stdin:  true,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/go-zoox/command/errors"
@@ -11,7 +12,23 @@ import (
 
 // Wait waits for the command to finish.
 func (p *podman) Wait() error {
-	resultC, errC := p.client.ContainerWait(context.Background(), p.container.ID, container.WaitConditionNotRunning)
+	defer p.egress.Remove(p.container.ID)
+	defer p.services.Remove(p.container.ID)
+	defer p.oom.Stop()
+
+	if p.stats != nil {
+		// let the final sample arrive before the container is gone
+		defer p.stats.Wait(time.Second)
+	}
+
+	resultC, errC := p.waitC, p.waitErrC
+	if resultC == nil {
+		resultC, errC = p.client.ContainerWait(context.Background(), p.container.ID, container.WaitConditionNotRunning)
+	}
+
+	// flush the demultiplexed output before returning, it ends when the container exits
+	defer p.flushOutput()
+
 	select {
 	case err := <-errC:
 		if err != nil && err != io.EOF {
@@ -19,6 +36,15 @@ func (p *podman) Wait() error {
 		}
 	case result := <-resultC:
 		if result.StatusCode != 0 {
+			if p.isOOMKilled() {
+				return &errors.ExitError{
+					Code:      int(result.StatusCode),
+					Message:   fmt.Sprintf("container exited with non-zero status: %d: killed by the OOM killer (memory limit: %dMB)", result.StatusCode, p.cfg.Memory),
+					OOMKilled: true,
+					Reason:    errors.ReasonOOMKilled,
+				}
+			}
+
 			return &errors.ExitError{
 				Code:    int(result.StatusCode),
 				Message: fmt.Sprintf("container exited with non-zero status: %d", result.StatusCode),
@@ -28,3 +54,20 @@ func (p *podman) Wait() error {
 
 	return nil
 }
+
+// waitCondition returns the condition to wait for before the container starts:
+// an auto removed container is waited until removal, a kept one until its next exit.
+func (p *podman) waitCondition() container.WaitCondition {
+	if p.cfg.IsAutoRemoveDisabled {
+		return container.WaitConditionNextExit
+	}
+
+	return container.WaitConditionRemoved
+}
+
+// flushOutput waits until the output of a container without TTY is copied.
+func (p *podman) flushOutput() {
+	if p.output != nil {
+		<-p.output
+	}
+}
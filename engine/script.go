@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"time"
+)
+
+// ScriptFileName is the file name of a staged script.
+const ScriptFileName = "script"
+
+// ScriptCommand returns the shell command running the script at path with interpreter.
+// The interpreter may carry arguments, e.g. `bash -euo pipefail`.
+func ScriptCommand(interpreter, path string) string {
+	if interpreter == "" {
+		interpreter = "/bin/sh"
+	}
+
+	return interpreter + " " + ShellQuote(path)
+}
+
+// ScriptArchive returns a tar stream containing the script as ScriptFileName.
+func ScriptArchive(script string) io.Reader {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{
+		Name:     ScriptFileName,
+		Mode:     0755,
+		Size:     int64(len(script)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	})
+	tw.Write([]byte(script))
+	tw.Close()
+
+	return buf
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Session is the interface that a long-lived execution environment must implement.
+// Every process executed by a session shares the same environment, including
+// installed packages, the working directory and exported environment variables.
+type Session interface {
+	Exec(cfg *ExecConfig) (Engine, error)
+	Close() error
+}
+
+// ExecConfig is the configuration for a process executed inside an existing environment.
+type ExecConfig struct {
+	Command     string
+	Environment map[string]string
+	WorkDir     string
+	User        string
+	Shell       string
+	// ReadOnly means none-interactive for terminal, which is used for show log, like top
+	ReadOnly bool
+}
+
+// WrapSessionCommand wraps the command so that the working directory and the
+// exported environment variables are restored from stateDir before it runs,
+// and saved back to stateDir when it exits.
+func WrapSessionCommand(stateDir string, command string) string {
+	return fmt.Sprintf(
+		`__go_zoox_session=%s; mkdir -p "$__go_zoox_session"; `+
+			`[ -f "$__go_zoox_session/env" ] && . "$__go_zoox_session/env" >/dev/null 2>&1; `+
+			`[ -f "$__go_zoox_session/cwd" ] && cd "$(cat "$__go_zoox_session/cwd")"; `+
+			`trap 'export -p > "$__go_zoox_session/env"; pwd > "$__go_zoox_session/cwd"' EXIT`+"\n%s",
+		ShellQuote(stateDir),
+		command,
+	)
+}
+
+// ShellQuote quotes s as a single shell word.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,18 @@
+package engine
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	for _, v := range []string{"", "hello", "it's", `a "b" $c`, "line\nbreak"} {
+		out, err := exec.Command("/bin/sh", "-c", "printf %s "+ShellQuote(v)).Output()
+		if err != nil {
+			t.Fatalf("sh: %v", err)
+		}
+		if string(out) != v {
+			t.Errorf("ShellQuote(%q) round trip = %q", v, out)
+		}
+	}
+}
@@ -8,7 +8,9 @@ func (s *ssh) Cancel() error {
 		}
 	}
 
-	if s.client != nil {
+	if s.client != nil && !s.isClientShared {
+		s.removeScript()
+
 		if err := s.client.Close(); err != nil {
 			return err
 		}
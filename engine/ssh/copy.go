@@ -0,0 +1,203 @@
+package ssh
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-zoox/command/archive"
+	"github.com/pkg/sftp"
+)
+
+// CopyTo extracts content into the directory dst of the remote server over SFTP.
+func (s *ssh) CopyTo(ctx context.Context, dst string, content io.Reader) error {
+	client, err := sftp.NewClient(s.client)
+	if err != nil {
+		return fmt.Errorf("failed to open sftp: %v", err)
+	}
+	defer client.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		client.Close()
+	})
+	defer stop()
+
+	if err := client.MkdirAll(dst); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(content)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean("/" + header.Name)
+		if name == "/" {
+			continue
+		}
+		target := path.Join(dst, name)
+		if err := checkRemoteParents(client, dst, target); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := client.MkdirAll(target); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := client.MkdirAll(path.Dir(target)); err != nil {
+				return err
+			}
+			// replace a symlink instead of writing through it
+			if info, err := client.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := client.Remove(target); err != nil {
+					return err
+				}
+			}
+
+			f, err := client.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			if err := client.Chmod(target, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := archive.CheckLink(header.Name, header.Linkname); err != nil {
+				return err
+			}
+
+			// a directory is not replaced, the targets of other symlinks may climb it
+			if info, err := client.Lstat(target); err == nil && info.IsDir() {
+				return fmt.Errorf("symlink %q would replace a directory", header.Name)
+			}
+			client.Remove(target)
+			if err := client.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkRemoteParents rejects the target if a directory between dst and it is a
+// symlink, which the entry would be written through.
+func checkRemoteParents(client *sftp.Client, dst, target string) error {
+	dir := path.Clean(dst)
+	rel := strings.TrimPrefix(path.Dir(target), dir)
+	for _, part := range strings.Split(strings.Trim(rel, "/"), "/") {
+		if part == "" {
+			continue
+		}
+
+		dir = path.Join(dir, part)
+		info, err := client.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("entry %q is below the symlink %q", target, dir)
+		}
+	}
+
+	return nil
+}
+
+// CopyFrom archives the file or directory src of the remote server over SFTP.
+func (s *ssh) CopyFrom(ctx context.Context, src string) (io.ReadCloser, error) {
+	client, err := sftp.NewClient(s.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sftp: %v", err)
+	}
+
+	src = path.Clean(src)
+	if _, err := client.Lstat(src); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer client.Close()
+
+		stop := context.AfterFunc(ctx, func() {
+			client.Close()
+		})
+		defer stop()
+
+		pw.CloseWithError(tarRemote(client, pw, src))
+	}()
+
+	return pr, nil
+}
+
+func tarRemote(client *sftp.Client, w io.Writer, src string) error {
+	tw := tar.NewWriter(w)
+
+	base := path.Dir(src)
+	walker := client.Walk(src)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return err
+		}
+
+		file, info := walker.Path(), walker.Stat()
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := client.ReadLink(file)
+			if err != nil {
+				return err
+			}
+			link = target
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = strings.TrimPrefix(strings.TrimPrefix(file, base), "/")
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		f, err := client.Open(file)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
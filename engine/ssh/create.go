@@ -9,6 +9,25 @@ import (
 )
 
 func (s *ssh) create() error {
+	if err := s.connect(); err != nil {
+		return err
+	}
+
+	if err := s.stageScript(); err != nil {
+		return err
+	}
+
+	var err error
+	s.session, err = s.client.NewSession()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// connect dials the ssh server.
+func (s *ssh) connect() error {
 	var err error
 
 	var hostkeyCallback sshx.HostKeyCallback
@@ -84,10 +103,5 @@ func (s *ssh) create() error {
 		return err
 	}
 
-	s.session, err = s.client.NewSession()
-	if err != nil {
-		return err
-	}
-
 	return nil
 }
@@ -0,0 +1,42 @@
+package ssh
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-zoox/command/engine"
+)
+
+// Exec creates a command running in a new channel of the same connection.
+func (s *ssh) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	channel, err := s.client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	command := cfg.Command
+	if cfg.WorkDir != "" {
+		command = fmt.Sprintf("cd %s || exit 1\n%s", engine.ShellQuote(cfg.WorkDir), command)
+	}
+
+	return &ssh{
+		cfg: &Config{
+			ID: s.cfg.ID,
+			//
+			Command:     command,
+			Environment: cfg.Environment,
+			WorkDir:     cfg.WorkDir,
+			Shell:       cfg.Shell,
+			ReadOnly:    cfg.ReadOnly,
+			//
+			AllowedSystemEnvKeys: s.cfg.AllowedSystemEnvKeys,
+		},
+		client:         s.client,
+		isClientShared: true,
+		session:        channel,
+		//
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/go-zoox/command/engine"
+	"github.com/pkg/sftp"
+)
+
+// scriptDir returns the remote directory holding the staged script.
+func (s *ssh) scriptDir() string {
+	return fmt.Sprintf("/tmp/.go-zoox-command-script-%s", s.cfg.ID)
+}
+
+// stageScript uploads the script over SFTP and runs it instead of the command.
+func (s *ssh) stageScript() error {
+	if s.cfg.Script == "" {
+		return nil
+	}
+
+	if err := s.CopyTo(context.Background(), s.scriptDir(), engine.ScriptArchive(s.cfg.Script)); err != nil {
+		return fmt.Errorf("failed to stage script: %v", err)
+	}
+
+	s.cfg.Command = engine.ScriptCommand(s.cfg.Interpreter, path.Join(s.scriptDir(), engine.ScriptFileName))
+	return nil
+}
+
+// removeScript removes the staged script.
+func (s *ssh) removeScript() {
+	if s.cfg.Script == "" {
+		return
+	}
+
+	client, err := sftp.NewClient(s.client)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	client.RemoveAll(s.scriptDir())
+}
@@ -0,0 +1,55 @@
+package ssh
+
+import (
+	"fmt"
+
+	"github.com/go-zoox/command/engine"
+)
+
+type session struct {
+	ssh *ssh
+	//
+	stateDir string
+}
+
+// NewSession creates a new ssh session, which keeps one connection open
+// and runs every command in a new channel of it.
+func NewSession(cfg *Config) (engine.Session, error) {
+	if cfg.Shell == "" {
+		cfg.Shell = "/bin/sh"
+	}
+
+	s := &ssh{
+		cfg: cfg,
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+
+	return &session{
+		ssh:      s,
+		stateDir: fmt.Sprintf("/tmp/.go-zoox-command-session-%s", cfg.ID),
+	}, nil
+}
+
+// Exec creates a command running in the session connection.
+func (s *session) Exec(cfg *engine.ExecConfig) (engine.Engine, error) {
+	return s.ssh.Exec(&engine.ExecConfig{
+		Command:     engine.WrapSessionCommand(s.stateDir, cfg.Command),
+		Environment: cfg.Environment,
+		WorkDir:     cfg.WorkDir,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		ReadOnly:    cfg.ReadOnly,
+	})
+}
+
+// Close removes the session state and closes the connection.
+func (s *session) Close() error {
+	if channel, err := s.ssh.client.NewSession(); err == nil {
+		channel.Run(fmt.Sprintf("rm -rf %s", engine.ShellQuote(s.stateDir)))
+		channel.Close()
+	}
+
+	return s.ssh.client.Close()
+}
@@ -19,6 +19,9 @@ type Config struct {
 	Shell       string
 	// ReadOnly means none-interactive for terminal, which is used for show log, like top
 	ReadOnly bool
+	// Script is uploaded over SFTP and run by Interpreter instead of Command
+	Script      string
+	Interpreter string
 	//
 	Host             string
 	Port             int
@@ -41,6 +44,8 @@ type ssh struct {
 	cfg *Config
 	//
 	client *sshx.Client
+	// isClientShared means the client is owned by a session and must not be closed
+	isClientShared bool
 	//
 	session *sshx.Session
 	//
@@ -2,5 +2,7 @@ package ssh
 
 // Wait waits for the command to exit.
 func (s *ssh) Wait() error {
+	defer s.removeScript()
+
 	return s.session.Wait()
 }
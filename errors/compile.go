@@ -0,0 +1,16 @@
+package errors
+
+// CompileError is the failure of the compile step of a language preset,
+// the program did not run.
+type CompileError struct {
+	*ExitError
+	// Output is the output of the compiler
+	Output string
+	// TimedOut means the compile step exceeded its timeout
+	TimedOut bool
+}
+
+// Unwrap returns the underlying exit error.
+func (e *CompileError) Unwrap() error {
+	return e.ExitError
+}
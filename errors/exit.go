@@ -1,9 +1,29 @@
 package errors
 
+// Termination reasons of a command which did not exit by itself.
+const (
+	// ReasonOOMKilled means the command was killed for exceeding its memory limit.
+	ReasonOOMKilled = "OOMKilled"
+	// ReasonTimeout means the command was killed for exceeding the Timeout of the config.
+	ReasonTimeout = "Timeout"
+	// ReasonSignal means the command was killed by a signal.
+	ReasonSignal = "Signal"
+	// ReasonEvicted means the pod of the command was evicted, e.g. for node pressure.
+	ReasonEvicted = "Evicted"
+	// ReasonDeadlineExceeded means the deadline of the context or the active deadline of the k8s job passed.
+	ReasonDeadlineExceeded = "DeadlineExceeded"
+)
+
 // ExitError is an error that indicates an exit.
 type ExitError struct {
 	Code    int
 	Message string
+	// OOMKilled means the command was killed for exceeding its memory limit
+	OOMKilled bool
+	// Reason is why the command ended if it did not exit by itself, e.g. OOMKilled or Signal
+	Reason string
+	// Signal is the name of the signal which killed the command, e.g. SIGKILL, with the Signal reason
+	Signal string
 }
 
 // Error returns the error message.
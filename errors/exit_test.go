@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"testing"
 )
 
@@ -23,3 +24,33 @@ func TestExitError_ExitCode(t *testing.T) {
 		t.Errorf("ExitCode() = %d, want 42", e.ExitCode())
 	}
 }
+
+func TestLimitExceededError_Unwrap(t *testing.T) {
+	var err error = &LimitExceededError{
+		ExitError: &ExitError{Code: 152, Message: "killed by SIGXCPU"},
+		Limit:     "cpu",
+		Signal:    "SIGXCPU",
+	}
+
+	var exitErr *ExitError
+	if !stderrors.As(err, &exitErr) || exitErr.ExitCode() != 152 {
+		t.Fatalf("expected to unwrap to ExitError with code 152, got %v", exitErr)
+	}
+	if err.Error() != "killed by SIGXCPU" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "killed by SIGXCPU")
+	}
+}
+
+func TestExitSignal(t *testing.T) {
+	for code, expected := range map[int]string{137: "SIGKILL", 143: "SIGTERM", 152: "SIGXCPU", 170: "signal 42"} {
+		if name := SignalName(ExitSignal(code)); name != expected {
+			t.Errorf("SignalName(ExitSignal(%d)) = %q, want %q", code, name, expected)
+		}
+	}
+
+	for _, code := range []int{0, 1, 127, 128, 255} {
+		if signal := ExitSignal(code); signal != 0 {
+			t.Errorf("ExitSignal(%d) = %d, want 0", code, signal)
+		}
+	}
+}
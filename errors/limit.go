@@ -0,0 +1,16 @@
+package errors
+
+// LimitExceededError is an exit caused by exceeding a resource limit,
+// e.g. the kernel sending SIGXCPU once the CPU time limit is reached.
+type LimitExceededError struct {
+	*ExitError
+	// Limit is the exceeded limit, e.g. cpu or fsize
+	Limit string
+	// Signal is the signal sent by the kernel, e.g. SIGXCPU
+	Signal string
+}
+
+// Unwrap returns the underlying exit error.
+func (e *LimitExceededError) Unwrap() error {
+	return e.ExitError
+}
@@ -0,0 +1,32 @@
+package errors
+
+import "fmt"
+
+// signalNames are the names of the linux signals by number.
+var signalNames = map[int]string{
+	1: "SIGHUP", 2: "SIGINT", 3: "SIGQUIT", 4: "SIGILL", 5: "SIGTRAP", 6: "SIGABRT",
+	7: "SIGBUS", 8: "SIGFPE", 9: "SIGKILL", 10: "SIGUSR1", 11: "SIGSEGV", 12: "SIGUSR2",
+	13: "SIGPIPE", 14: "SIGALRM", 15: "SIGTERM", 16: "SIGSTKFLT", 17: "SIGCHLD", 18: "SIGCONT",
+	19: "SIGSTOP", 20: "SIGTSTP", 21: "SIGTTIN", 22: "SIGTTOU", 23: "SIGURG", 24: "SIGXCPU",
+	25: "SIGXFSZ", 26: "SIGVTALRM", 27: "SIGPROF", 28: "SIGWINCH", 29: "SIGIO", 30: "SIGPWR",
+	31: "SIGSYS",
+}
+
+// SignalName returns the name of the signal number, e.g. SIGKILL for 9.
+func SignalName(signal int) string {
+	if name, ok := signalNames[signal]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("signal %d", signal)
+}
+
+// ExitSignal returns the signal number reported by the exit code 128+n, as shells and
+// container runtimes do for a process killed by a signal, 0 for other exit codes.
+func ExitSignal(code int) int {
+	if code > 128 && code <= 128+64 {
+		return code - 128
+	}
+
+	return 0
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/go-zoox/command/engine"
+)
+
+// Exec runs an additional process inside the environment of the running command,
+// e.g. the same container or Pod. Unset fields of cfg are inherited from the command.
+func (c *command) Exec(cfg *Config) (Command, error) {
+	execer, ok := c.engine.(engine.Execer)
+	if !ok {
+		return nil, fmt.Errorf("exec is not supported by engine: %s", c.cfg.Engine)
+	}
+
+	child := *c.cfg
+	resetChildConfig(&child)
+	child.Command = cfg.Command
+	child.ReadOnly = cfg.ReadOnly
+	child.Timeout = cfg.Timeout
+	if cfg.Context != nil {
+		child.Context = cfg.Context
+	}
+	if cfg.WorkDir != "" {
+		child.WorkDir = cfg.WorkDir
+	}
+	if cfg.User != "" {
+		child.User = cfg.User
+	}
+	if cfg.Shell != "" {
+		child.Shell = cfg.Shell
+	}
+
+	child.Environment = map[string]string{}
+	for k, v := range c.cfg.Environment {
+		child.Environment[k] = v
+	}
+	for k, v := range cfg.Environment {
+		child.Environment[k] = v
+	}
+
+	eg, err := execer.Exec(&engine.ExecConfig{
+		Command:     child.Command,
+		Environment: child.Environment,
+		WorkDir:     child.WorkDir,
+		User:        child.User,
+		Shell:       child.Shell,
+		ReadOnly:    child.ReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newCommand(&child, eg), nil
+}
+
+// resetChildConfig clears the settings which belong to the command owning the
+// environment, so a child does not collect its artifacts, report its changes,
+// compile its script or keep and clean up its container.
+func resetChildConfig(cfg *Config) {
+	cfg.Script = ""
+	cfg.Interpreter = ""
+	cfg.Language = ""
+	cfg.CompileTimeout = 0
+	cfg.Artifacts = nil
+	cfg.ArtifactsDir = ""
+	cfg.ArtifactsWriter = nil
+	cfg.ArtifactsMaxSize = 0
+	cfg.ArtifactsMaxFileSize = 0
+	cfg.ReportChanges = false
+	cfg.KeepContainer = ""
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExec_SameWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	cmd, err := New(&Config{
+		Command: "sleep 5",
+		WorkDir: dir,
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	cmd.SetStdout(&strings.Builder{})
+	cmd.SetStderr(&strings.Builder{})
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer cmd.Cancel()
+
+	child, err := cmd.Exec(&Config{
+		Command: "pwd; echo $EXTRA",
+		Environment: map[string]string{
+			"EXTRA": "side",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Exec() failed: %v", err)
+	}
+	out, err := child.Output()
+	if err != nil {
+		t.Fatalf("child Output() failed: %v", err)
+	}
+	if v := string(out); !strings.Contains(v, dir) || !strings.Contains(v, "side") {
+		t.Errorf("expected child output to contain workdir and env, got %q", v)
+	}
+}
+
+func TestExec_DoesNotInheritArtifacts(t *testing.T) {
+	workdir := t.TempDir()
+	stream := &bytes.Buffer{}
+	cmd, err := New(&Config{
+		Command:         "echo app > app.txt; sleep 5",
+		WorkDir:         workdir,
+		Artifacts:       []string{"*.txt"},
+		ArtifactsWriter: stream,
+		ReportChanges:   true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	cmd.SetStdout(&strings.Builder{})
+	cmd.SetStderr(&strings.Builder{})
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer cmd.Cancel()
+
+	child, err := cmd.Exec(&Config{Command: "echo side > side.txt"})
+	if err != nil {
+		t.Fatalf("Exec() failed: %v", err)
+	}
+	if _, err := child.Output(); err != nil {
+		t.Fatalf("child Output() failed: %v", err)
+	}
+
+	r := child.Result()
+	if r == nil {
+		t.Fatal("expected result after Output")
+	}
+	if len(r.Artifacts) != 0 || len(r.Changes) != 0 {
+		t.Errorf("expected the child to collect no artifacts and changes, got %+v %+v", r.Artifacts, r.Changes)
+	}
+	if stream.Len() != 0 {
+		t.Errorf("expected the child not to write the artifacts of the parent, got %d bytes", stream.Len())
+	}
+}
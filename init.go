@@ -8,6 +8,7 @@ import (
 	"github.com/go-zoox/command/engine/docker"
 	"github.com/go-zoox/command/engine/host"
 	"github.com/go-zoox/command/engine/k8s"
+	"github.com/go-zoox/command/engine/namespace"
 	"github.com/go-zoox/command/engine/podman"
 
 	"github.com/go-zoox/command/engine/ssh"
@@ -22,23 +23,7 @@ func init() {
 
 	// Register the host engine
 	engine.Register(host.Name, func(cfg *config.Config) (engine.Engine, error) {
-		engine, err := host.New(&host.Config{
-			ID: cfg.ID,
-			//
-			Command:     cfg.Command,
-			WorkDir:     cfg.WorkDir,
-			Environment: cfg.Environment,
-			User:        cfg.User,
-			Shell:       cfg.Shell,
-			//
-			ReadOnly: cfg.ReadOnly,
-			//
-			IsHistoryDisabled: cfg.IsHistoryDisabled,
-			//
-			IsInheritEnvironmentEnabled: cfg.IsInheritEnvironmentEnabled,
-			//
-			AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
-		})
+		engine, err := host.New(newHostConfig(cfg))
 		if err != nil {
 			return nil, err
 		}
@@ -48,39 +33,7 @@ func init() {
 
 	// Register the docker engine
 	engine.Register(docker.Name, func(cfg *config.Config) (engine.Engine, error) {
-		engine, err := docker.New(&docker.Config{
-			ID: cfg.ID,
-			//
-			Command:     cfg.Command,
-			WorkDir:     cfg.WorkDir,
-			Environment: cfg.Environment,
-			User:        cfg.User,
-			Shell:       cfg.Shell,
-			//
-			ReadOnly: cfg.ReadOnly,
-			//
-			Image:          cfg.Image,
-			Memory:         cfg.Memory,
-			CPU:            cfg.CPU,
-			Platform:       cfg.Platform,
-			Network:        cfg.Network,
-			DisableNetwork: cfg.DisableNetwork,
-			Privileged:     cfg.Privileged,
-			//
-			DockerHost: cfg.DockerHost,
-			//
-			ImageRegistry:         cfg.ImageRegistry,
-			ImageRegistryUsername: cfg.ImageRegistryUsername,
-			ImageRegistryPassword: cfg.ImageRegistryPassword,
-			Runtime:               cfg.DockerRuntime,
-			//
-			AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
-			//
-			DataDirOuter: cfg.DataDirOuter,
-			DataDirInner: cfg.DataDirInner,
-			//
-			Sandbox: cfg.Sandbox,
-		})
+		engine, err := docker.New(newDockerConfig(cfg))
 		if err != nil {
 			return nil, err
 		}
@@ -90,23 +43,7 @@ func init() {
 
 	// Register the caas engine
 	engine.Register(caas.Name, func(cfg *config.Config) (engine.Engine, error) {
-		engine, err := caas.New(&caas.Config{
-			ID: cfg.ID,
-			//
-			Command:     cfg.Command,
-			WorkDir:     cfg.WorkDir,
-			Environment: cfg.Environment,
-			User:        cfg.User,
-			Shell:       cfg.Shell,
-			//
-			ReadOnly: cfg.ReadOnly,
-			//
-			Server:       cfg.Server,
-			ClientID:     cfg.ClientID,
-			ClientSecret: cfg.ClientSecret,
-			//
-			AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
-		})
+		engine, err := caas.New(newCaasConfig(cfg))
 		if err != nil {
 			return nil, err
 		}
@@ -116,28 +53,7 @@ func init() {
 
 	// Register the k8s engine
 	engine.Register(k8s.Name, func(cfg *config.Config) (engine.Engine, error) {
-		k8sImage := cfg.K8sImage
-		if k8sImage == "" {
-			k8sImage = cfg.Image
-		}
-		engine, err := k8s.New(&k8s.Config{
-			ID: cfg.ID,
-			//
-			Command:     cfg.Command,
-			WorkDir:     cfg.WorkDir,
-			Environment: cfg.Environment,
-			User:        cfg.User,
-			Shell:       cfg.Shell,
-			//
-			ReadOnly: cfg.ReadOnly,
-			//
-			Kubeconfig:         cfg.K8sKubeconfig,
-			Namespace:         cfg.K8sNamespace,
-			Image:             k8sImage,
-			JobTimeoutSeconds: cfg.K8sPodTimeoutSeconds,
-			//
-			AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
-		})
+		engine, err := k8s.New(newK8sConfig(cfg))
 		if err != nil {
 			return nil, err
 		}
@@ -147,33 +63,7 @@ func init() {
 
 	// Register the podman engine
 	engine.Register(podman.Name, func(cfg *config.Config) (engine.Engine, error) {
-		podmanImage := cfg.Image
-		if podmanImage == "" {
-			podmanImage = "docker.io/library/alpine:latest"
-		}
-		engine, err := podman.New(&podman.Config{
-			ID: cfg.ID,
-			//
-			Command:     cfg.Command,
-			WorkDir:     cfg.WorkDir,
-			Environment: cfg.Environment,
-			User:        cfg.User,
-			Shell:       cfg.Shell,
-			//
-			ReadOnly: cfg.ReadOnly,
-			//
-			Image:          podmanImage,
-			Memory:         cfg.Memory,
-			CPU:            cfg.CPU,
-			Platform:       cfg.Platform,
-			Network:        cfg.Network,
-			DisableNetwork: cfg.DisableNetwork,
-			Privileged:     cfg.Privileged,
-			//
-			PodmanHost: cfg.PodmanHost,
-			//
-			AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
-		})
+		engine, err := podman.New(newPodmanConfig(cfg))
 		if err != nil {
 			return nil, err
 		}
@@ -183,21 +73,7 @@ func init() {
 
 	// Register the wsl engine (Windows only)
 	engine.Register(wsl.Name, func(cfg *config.Config) (engine.Engine, error) {
-		engine, err := wsl.New(&wsl.Config{
-			ID: cfg.ID,
-			//
-			Command:     cfg.Command,
-			WorkDir:     cfg.WorkDir,
-			Environment: cfg.Environment,
-			User:        cfg.User,
-			Shell:       cfg.Shell,
-			//
-			ReadOnly: cfg.ReadOnly,
-			//
-			WSLDistro: cfg.WSLDistro,
-			//
-			AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
-		})
+		engine, err := wsl.New(newWSLConfig(cfg))
 		if err != nil {
 			return nil, err
 		}
@@ -206,26 +82,7 @@ func init() {
 
 	// Register the dind engine
 	engine.Register(dind.Name, func(cfg *config.Config) (engine.Engine, error) {
-		engine, err := dind.New(&dind.Config{
-			ID: cfg.ID,
-			//
-			Command:     cfg.Command,
-			WorkDir:     cfg.WorkDir,
-			Environment: cfg.Environment,
-			User:        cfg.User,
-			Shell:       cfg.Shell,
-			//
-			ReadOnly: cfg.ReadOnly,
-			//
-			Image:          cfg.Image,
-			Memory:         cfg.Memory,
-			CPU:            cfg.CPU,
-			Platform:       cfg.Platform,
-			Network:        cfg.Network,
-			DisableNetwork: cfg.DisableNetwork,
-			//
-			AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
-		})
+		engine, err := dind.New(newDindConfig(cfg))
 		if err != nil {
 			return nil, err
 		}
@@ -235,33 +92,412 @@ func init() {
 
 	// Register the ssh engine
 	engine.Register(ssh.Name, func(cfg *config.Config) (engine.Engine, error) {
-		engine, err := ssh.New(&ssh.Config{
-			ID: cfg.ID,
-			//
-			Command:     cfg.Command,
-			WorkDir:     cfg.WorkDir,
-			Environment: cfg.Environment,
-			// User:        cfg.User,
-			Shell: cfg.Shell,
-			//
-			ReadOnly: cfg.ReadOnly,
-			//
-			Host:             cfg.SSHHost,
-			Port:             cfg.SSHPort,
-			User:             cfg.SSHUser,
-			Pass:             cfg.SSHPass,
-			PrivateKey:       cfg.SSHPrivateKey,
-			PrivateKeySecret: cfg.SSHPrivateKeySecret,
-			//
-			IsIgnoreStrictHostKeyChecking: cfg.SSHIsIgnoreStrictHostKeyChecking,
-			KnowHostsFilePath:             cfg.SSHKnowHostsFilePath,
-			//
-			AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
-		})
+		engine, err := ssh.New(newSSHConfig(cfg))
+		if err != nil {
+			return nil, err
+		}
+
+		return engine, nil
+	})
+
+	// Register the namespace engine (Linux only)
+	engine.Register(namespace.Name, func(cfg *config.Config) (engine.Engine, error) {
+		engine, err := namespace.New(newNamespaceConfig(cfg))
 		if err != nil {
 			return nil, err
 		}
 
 		return engine, nil
 	})
+
+	// Register the sessions
+
+	// Register the host session
+	engine.RegisterSession(host.Name, func(cfg *config.Config) (engine.Session, error) {
+		return host.NewSession(newHostConfig(cfg))
+	})
+
+	// Register the docker session
+	engine.RegisterSession(docker.Name, func(cfg *config.Config) (engine.Session, error) {
+		return docker.NewSession(newDockerConfig(cfg))
+	})
+
+	// Register the k8s session
+	engine.RegisterSession(k8s.Name, func(cfg *config.Config) (engine.Session, error) {
+		return k8s.NewSession(newK8sConfig(cfg))
+	})
+
+	// Register the podman session
+	engine.RegisterSession(podman.Name, func(cfg *config.Config) (engine.Session, error) {
+		return podman.NewSession(newPodmanConfig(cfg))
+	})
+
+	// Register the dind session
+	engine.RegisterSession(dind.Name, func(cfg *config.Config) (engine.Session, error) {
+		return dind.NewSession(newDindConfig(cfg))
+	})
+
+	// Register the ssh session
+	engine.RegisterSession(ssh.Name, func(cfg *config.Config) (engine.Session, error) {
+		return ssh.NewSession(newSSHConfig(cfg))
+	})
+}
+
+// newHostConfig creates the host engine config from the command config.
+func newHostConfig(cfg *config.Config) *host.Config {
+	return &host.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		//
+		Script:      cfg.Script,
+		Interpreter: cfg.Interpreter,
+		//
+		IsHistoryDisabled: cfg.IsHistoryDisabled,
+		//
+		IsInheritEnvironmentEnabled: cfg.IsInheritEnvironmentEnabled,
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+		//
+		Memory:       cfg.Memory,
+		CPU:          cfg.CPU,
+		PidsLimit:    cfg.PidsLimit,
+		IOLimits:     cfg.IOLimits,
+		CgroupParent: cfg.CgroupParent,
+		//
+		Limits: cfg.Limits,
+		//
+		FSPolicy: cfg.FSPolicy,
+		Seccomp:  cfg.Seccomp,
+	}
+}
+
+// newNamespaceConfig creates the namespace engine config from the command config.
+func newNamespaceConfig(cfg *config.Config) *namespace.Config {
+	return &namespace.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		//
+		DisableNetwork: cfg.DisableNetwork,
+		EgressAllow:    cfg.EgressAllow,
+		Hostname:       cfg.Hostname,
+		//
+		Tmpfs:   namespaceTmpfs(cfg),
+		Seccomp: cfg.Seccomp,
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+	}
+}
+
+// namespaceTmpfs returns the tmpfs mounts of the sandbox profile.
+func namespaceTmpfs(cfg *config.Config) map[string]string {
+	if profile := sandboxProfile(cfg); profile != nil {
+		return profile.Tmpfs
+	}
+
+	return nil
+}
+
+// newDockerConfig creates the docker engine config from the command config.
+func newDockerConfig(cfg *config.Config) *docker.Config {
+	return &docker.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		TTY:      cfg.TTY,
+		//
+		Script:      cfg.Script,
+		Interpreter: cfg.Interpreter,
+		//
+		Image:          cfg.Image,
+		Memory:         cfg.Memory,
+		CPU:            cfg.CPU,
+		PidsLimit:      cfg.PidsLimit,
+		Platform:       cfg.Platform,
+		Network:        cfg.Network,
+		DisableNetwork: cfg.DisableNetwork,
+		EgressAllow:    cfg.EgressAllow,
+		Services:       cfg.Services,
+		Privileged:     cfg.Privileged,
+		//
+		MemorySwap:        cfg.MemorySwap,
+		MemoryReservation: cfg.MemoryReservation,
+		CPUSet:            cfg.CPUSet,
+		ShmSize:           cfg.ShmSize,
+		Ulimits:           cfg.Ulimits,
+		BlkioWeight:       cfg.BlkioWeight,
+		StorageSize:       cfg.StorageSize,
+		//
+		Networks:       cfg.Networks,
+		NetworkAliases: cfg.NetworkAliases,
+		Hostname:       cfg.Hostname,
+		Ports:          cfg.Ports,
+		DNS:            cfg.DNS,
+		ExtraHosts:     cfg.ExtraHosts,
+		//
+		DockerHost: cfg.DockerHost,
+		//
+		ImageRegistry:         cfg.ImageRegistry,
+		ImageRegistryUsername: cfg.ImageRegistryUsername,
+		ImageRegistryPassword: cfg.ImageRegistryPassword,
+		Runtime:               cfg.DockerRuntime,
+		PullPolicy:            cfg.PullPolicy,
+		RegistryAuths:         cfg.RegistryAuths,
+		Build:                 cfg.Build,
+		//
+		Mounts:              cfg.Mounts,
+		DisableWorkDirMount: cfg.DisableWorkDirMount,
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+		//
+		DataDirOuter: cfg.DataDirOuter,
+		DataDirInner: cfg.DataDirInner,
+		//
+		Sandbox:        cfg.Sandbox,
+		SandboxProfile: sandboxProfile(cfg),
+		Seccomp:        cfg.Seccomp,
+		//
+		IsAutoRemoveDisabled: isAutoRemoveDisabled(cfg),
+	}
+}
+
+// newCaasConfig creates the caas engine config from the command config.
+func newCaasConfig(cfg *config.Config) *caas.Config {
+	return &caas.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		//
+		Server:       cfg.Server,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+	}
+}
+
+// newK8sConfig creates the k8s engine config from the command config.
+func newK8sConfig(cfg *config.Config) *k8s.Config {
+	k8sImage := cfg.K8sImage
+	if k8sImage == "" {
+		k8sImage = cfg.Image
+	}
+
+	return &k8s.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		//
+		Script:      cfg.Script,
+		Interpreter: cfg.Interpreter,
+		//
+		Kubeconfig:        cfg.K8sKubeconfig,
+		Namespace:         cfg.K8sNamespace,
+		Image:             k8sImage,
+		JobTimeoutSeconds: cfg.K8sPodTimeoutSeconds,
+		PullPolicy:        cfg.PullPolicy,
+		//
+		Memory:            cfg.Memory,
+		CPU:               cfg.CPU,
+		MemoryReservation: cfg.MemoryReservation,
+		ShmSize:           cfg.ShmSize,
+		StorageSize:       cfg.StorageSize,
+		DisableNetwork:    cfg.DisableNetwork,
+		Mounts:            cfg.Mounts,
+		Sandbox:           cfg.Sandbox,
+		SandboxProfile:    sandboxProfile(cfg),
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+	}
+}
+
+// newPodmanConfig creates the podman engine config from the command config.
+func newPodmanConfig(cfg *config.Config) *podman.Config {
+	podmanImage := cfg.Image
+	if podmanImage == "" {
+		podmanImage = "docker.io/library/alpine:latest"
+	}
+
+	return &podman.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		TTY:      cfg.TTY,
+		//
+		Script:      cfg.Script,
+		Interpreter: cfg.Interpreter,
+		//
+		Image:          podmanImage,
+		Memory:         cfg.Memory,
+		CPU:            cfg.CPU,
+		Platform:       cfg.Platform,
+		Network:        cfg.Network,
+		DisableNetwork: cfg.DisableNetwork,
+		EgressAllow:    cfg.EgressAllow,
+		Services:       cfg.Services,
+		Privileged:     cfg.Privileged,
+		//
+		MemorySwap:        cfg.MemorySwap,
+		MemoryReservation: cfg.MemoryReservation,
+		CPUSet:            cfg.CPUSet,
+		ShmSize:           cfg.ShmSize,
+		Ulimits:           cfg.Ulimits,
+		BlkioWeight:       cfg.BlkioWeight,
+		StorageSize:       cfg.StorageSize,
+		//
+		Networks:       cfg.Networks,
+		NetworkAliases: cfg.NetworkAliases,
+		Hostname:       cfg.Hostname,
+		Ports:          cfg.Ports,
+		DNS:            cfg.DNS,
+		ExtraHosts:     cfg.ExtraHosts,
+		//
+		Mounts: cfg.Mounts,
+		//
+		PullPolicy:    cfg.PullPolicy,
+		RegistryAuths: cfg.RegistryAuths,
+		Build:         cfg.Build,
+		//
+		ImageRegistry:         cfg.ImageRegistry,
+		ImageRegistryUsername: cfg.ImageRegistryUsername,
+		ImageRegistryPassword: cfg.ImageRegistryPassword,
+		//
+		PodmanHost: cfg.PodmanHost,
+		//
+		PidsLimit:      cfg.PidsLimit,
+		Sandbox:        cfg.Sandbox,
+		SandboxProfile: sandboxProfile(cfg),
+		Seccomp:        cfg.Seccomp,
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+		//
+		IsAutoRemoveDisabled: isAutoRemoveDisabled(cfg),
+	}
+}
+
+// newWSLConfig creates the wsl engine config from the command config.
+func newWSLConfig(cfg *config.Config) *wsl.Config {
+	return &wsl.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		//
+		WSLDistro: cfg.WSLDistro,
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+	}
+}
+
+// newDindConfig creates the dind engine config from the command config.
+func newDindConfig(cfg *config.Config) *dind.Config {
+	return &dind.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		TTY:      cfg.TTY,
+		//
+		Script:      cfg.Script,
+		Interpreter: cfg.Interpreter,
+		//
+		Image:          cfg.Image,
+		Memory:         cfg.Memory,
+		CPU:            cfg.CPU,
+		Platform:       cfg.Platform,
+		Network:        cfg.Network,
+		DisableNetwork: cfg.DisableNetwork,
+		//
+		Mounts:              cfg.Mounts,
+		DisableWorkDirMount: cfg.DisableWorkDirMount,
+		//
+		PullPolicy:    cfg.PullPolicy,
+		RegistryAuths: cfg.RegistryAuths,
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+		//
+		IsAutoRemoveDisabled: isAutoRemoveDisabled(cfg),
+	}
+}
+
+// newSSHConfig creates the ssh engine config from the command config.
+func newSSHConfig(cfg *config.Config) *ssh.Config {
+	return &ssh.Config{
+		ID: cfg.ID,
+		//
+		Command:     cfg.Command,
+		WorkDir:     cfg.WorkDir,
+		Environment: cfg.Environment,
+		// User:        cfg.User,
+		Shell: cfg.Shell,
+		//
+		ReadOnly: cfg.ReadOnly,
+		//
+		Script:      cfg.Script,
+		Interpreter: cfg.Interpreter,
+		//
+		Host:             cfg.SSHHost,
+		Port:             cfg.SSHPort,
+		User:             cfg.SSHUser,
+		Pass:             cfg.SSHPass,
+		PrivateKey:       cfg.SSHPrivateKey,
+		PrivateKeySecret: cfg.SSHPrivateKeySecret,
+		//
+		IsIgnoreStrictHostKeyChecking: cfg.SSHIsIgnoreStrictHostKeyChecking,
+		KnowHostsFilePath:             cfg.SSHKnowHostsFilePath,
+		//
+		AllowedSystemEnvKeys: cfg.AllowedSystemEnvKeys,
+	}
+}
+
+// isAutoRemoveDisabled reports whether the container has to outlive the command
+// for work done after exit, e.g. collecting artifacts.
+func isAutoRemoveDisabled(cfg *config.Config) bool {
+	return len(cfg.Artifacts) != 0 || cfg.ReportChanges || cfg.KeepContainer != ""
 }
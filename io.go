@@ -18,6 +18,10 @@ func (c *command) SetStdout(stdout io.Writer) error {
 
 // SetStderr sets the stderr for the command.
 func (c *command) SetStderr(stderr io.Writer) error {
+	if c.compile != nil {
+		stderr = c.compile.wrap(stderr)
+	}
+
 	return c.engine.SetStderr(stderr)
 }
 
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/dind"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/podman"
+)
+
+// checkKeepContainer validates KeepContainer for the engine.
+func checkKeepContainer(cfg *Config) error {
+	switch cfg.KeepContainer {
+	case config.KeepAlways, config.KeepOnFailure:
+	default:
+		return fmt.Errorf("invalid keep container: %s, available: always, on-failure", cfg.KeepContainer)
+	}
+
+	switch cfg.Engine {
+	case docker.Name, podman.Name, dind.Name:
+	default:
+		return fmt.Errorf("keep container is only supported by docker, podman and dind engines, but got: %s", cfg.Engine)
+	}
+
+	return nil
+}
+
+// isContainerKept reports whether the container is kept after the command exited with the code.
+func isContainerKept(cfg *Config, exitCode int) bool {
+	switch cfg.KeepContainer {
+	case config.KeepAlways:
+		return true
+	case config.KeepOnFailure:
+		return exitCode != 0
+	default:
+		return false
+	}
+}
+
+// Commit snapshots the container of the command into the image ref, e.g. the
+// container kept after a failure, and returns the image ID.
+func (c *command) Commit(ref string) (string, error) {
+	committer, ok := c.engine.(engine.Committer)
+	if !ok {
+		return "", fmt.Errorf("commit is not supported by engine: %s", c.cfg.Engine)
+	}
+
+	// the container of a finished command is gone unless it was kept
+	if c.result != nil && c.result.Container == "" {
+		return "", fmt.Errorf("container is removed after exit, use KeepContainer to commit it")
+	}
+
+	return committer.Commit(c.cfg.Context, ref)
+}
+
+// Cleanup removes the container kept after exit.
+func (c *command) Cleanup() error {
+	if c.result == nil || c.result.Container == "" {
+		return nil
+	}
+
+	cleaner, ok := c.engine.(engine.Cleaner)
+	if !ok {
+		return fmt.Errorf("cleanup is not supported by engine: %s", c.cfg.Engine)
+	}
+
+	if err := cleaner.Cleanup(); err != nil {
+		return err
+	}
+
+	c.result.Container = ""
+	return nil
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckKeepContainer(t *testing.T) {
+	for _, tc := range []struct {
+		engine string
+		keep   string
+		err    string
+	}{
+		{"docker", "always", ""},
+		{"podman", "on-failure", ""},
+		{"dind", "always", ""},
+		{"docker", "never", "invalid keep container"},
+		{"host", "always", "only supported by"},
+		{"k8s", "on-failure", "only supported by"},
+	} {
+		err := checkKeepContainer(&Config{Engine: tc.engine, KeepContainer: tc.keep})
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", tc.engine, tc.keep, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s %s: expected error containing %q, got %v", tc.engine, tc.keep, tc.err, err)
+		}
+	}
+}
+
+func TestIsContainerKept(t *testing.T) {
+	for _, tc := range []struct {
+		keep     string
+		exitCode int
+		expected bool
+	}{
+		{"", 1, false},
+		{"always", 0, true},
+		{"always", 1, true},
+		{"on-failure", 0, false},
+		{"on-failure", 2, true},
+		{"on-failure", -1, true},
+	} {
+		if kept := isContainerKept(&Config{KeepContainer: tc.keep}, tc.exitCode); kept != tc.expected {
+			t.Errorf("%q with exit code %d: expected kept %t, got %t", tc.keep, tc.exitCode, tc.expected, kept)
+		}
+	}
+}
+
+func TestKeepContainer_Host(t *testing.T) {
+	if _, err := New(&Config{Command: "true", KeepContainer: "always"}); err == nil {
+		t.Fatal("expected an error for the host engine")
+	}
+}
@@ -0,0 +1,285 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/command/engine"
+	cmderrors "github.com/go-zoox/command/errors"
+	"github.com/go-zoox/command/language"
+	"github.com/go-zoox/command/result"
+)
+
+// LanguagePreset describes how the source code of a language is staged, compiled and run.
+type LanguagePreset = language.Preset
+
+// RegisterLanguage registers a custom language preset.
+func RegisterLanguage(name string, preset *LanguagePreset) error {
+	return language.Register(name, preset)
+}
+
+// maxCompileOutput is the limit of the compile output kept in the Result.
+const maxCompileOutput = 64 << 10
+
+// applyLanguage selects the image of the language preset and replaces the
+// source in Script with a script staging, compiling and running it.
+func applyLanguage(cfg *Config) error {
+	preset, err := language.Get(cfg.Language)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, cfg.Language)
+	}
+
+	if cfg.Script == "" {
+		return fmt.Errorf("language %s requires the source code in Script", cfg.Language)
+	}
+	if cfg.Command != "" {
+		return fmt.Errorf("command and language are mutually exclusive")
+	}
+	// the compile step is told apart on stderr, which a tty merges into stdout
+	if cfg.TTY {
+		return fmt.Errorf("language %s does not support TTY", cfg.Language)
+	}
+
+	// with Build, the image is built instead
+	if cfg.Image == "" && cfg.Build == nil {
+		cfg.Image = preset.Image
+	}
+
+	compileTimeout := preset.CompileTimeout
+	if cfg.CompileTimeout != 0 {
+		compileTimeout = cfg.CompileTimeout
+	}
+
+	cfg.Script = languageScript(preset, cfg.Script, compileMarker(cfg), compileTimeout)
+	cfg.Interpreter = "/bin/sh"
+	return nil
+}
+
+// compileMarker frames the output of the compile step, so it can be told
+// apart from the output of the program.
+func compileMarker(cfg *Config) string {
+	return "go-zoox-command-compile-" + cfg.ID
+}
+
+// languageScript returns the script staging the source next to the script (or
+// in a temporary directory if that is read-only), compiling it and running it.
+// The compile step prints the begin and end markers with its exit code to stderr.
+func languageScript(preset *LanguagePreset, source, marker string, compileTimeout time.Duration) string {
+	delimiter := "GO_ZOOX_COMMAND_SOURCE_" + strings.ReplaceAll(marker, "-", "_")
+
+	s := &strings.Builder{}
+	s.WriteString("dir=\"$(dirname \"$0\")/src\"\n")
+	s.WriteString("{ mkdir -p \"$dir\" && [ -w \"$dir\" ]; } 2>/dev/null || dir=\"$(mktemp -d)\"\n")
+	s.WriteString("cd \"$dir\" || exit 1\n")
+	fmt.Fprintf(s, "cat > %s <<'%s'\n%s\n%s\n", engine.ShellQuote(preset.FileName), delimiter, strings.TrimSuffix(source, "\n"), delimiter)
+
+	if preset.Compile != "" {
+		compile := "sh -c " + engine.ShellQuote(preset.Compile)
+		if compileTimeout != 0 {
+			compile = fmt.Sprintf("if command -v timeout >/dev/null 2>&1; then timeout %d %s; else %s; fi", int(compileTimeout.Seconds()+0.5), compile, compile)
+		}
+
+		fmt.Fprintf(s, "printf '%%s:begin\\n' %s >&2\n", engine.ShellQuote(marker))
+		fmt.Fprintf(s, "{ %s; } </dev/null 1>&2\n", compile)
+		s.WriteString("code=$?\n")
+		fmt.Fprintf(s, "printf '%%s:end:%%d\\n' %s \"$code\" >&2\n", engine.ShellQuote(marker))
+		s.WriteString("[ \"$code\" -eq 0 ] || exit \"$code\"\n")
+	}
+
+	s.WriteString(preset.Run + "\n")
+	return s.String()
+}
+
+// compileOutput collects the compile step from the output streams of the command.
+type compileOutput struct {
+	marker        string
+	isTimeoutUsed bool
+	//
+	sync.Mutex
+	capturing bool
+	output    bytes.Buffer
+	result    *result.Compile
+	writers   []*compileWriter
+}
+
+func newCompileOutput(cfg *Config) *compileOutput {
+	preset, err := language.Get(cfg.Language)
+	if err != nil || preset.Compile == "" {
+		return nil
+	}
+
+	return &compileOutput{
+		marker:        compileMarker(cfg),
+		isTimeoutUsed: preset.CompileTimeout != 0 || cfg.CompileTimeout != 0,
+	}
+}
+
+// wrap returns a writer forwarding to w without the markers.
+func (o *compileOutput) wrap(w io.Writer) io.Writer {
+	o.Lock()
+	defer o.Unlock()
+
+	cw := &compileWriter{output: o, w: w}
+	o.writers = append(o.writers, cw)
+	return cw
+}
+
+// finish flushes the writers and returns the outcome of the compile step, nil if it did not finish.
+func (o *compileOutput) finish() *result.Compile {
+	for _, w := range o.writers {
+		w.flush()
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	return o.result
+}
+
+// marked handles the rest of a marker line, i.e. :begin or :end:<code>.
+func (o *compileOutput) marked(line string) {
+	o.Lock()
+	defer o.Unlock()
+
+	// the program runs after the compile step and may print markers itself
+	if o.result != nil {
+		return
+	}
+
+	switch {
+	case line == ":begin":
+		o.capturing = true
+	case strings.HasPrefix(line, ":end:"):
+		code, _ := strconv.Atoi(strings.TrimPrefix(line, ":end:"))
+		o.capturing = false
+		o.result = &result.Compile{
+			ExitCode: code,
+			// timeout exits with 124 when the command times out
+			TimedOut: o.isTimeoutUsed && code == 124,
+			Output:   o.output.String(),
+		}
+	}
+}
+
+// isFinished reports whether the compile step has finished.
+func (o *compileOutput) isFinished() bool {
+	o.Lock()
+	defer o.Unlock()
+
+	return o.result != nil
+}
+
+func (o *compileOutput) capture(p []byte) {
+	o.Lock()
+	defer o.Unlock()
+
+	if o.capturing && o.output.Len() < maxCompileOutput {
+		if n := maxCompileOutput - o.output.Len(); len(p) > n {
+			p = p[:n]
+		}
+		o.output.Write(p)
+	}
+}
+
+// compileWriter removes the marker lines from a stream, holding back the
+// bytes at the end of a write which may start a marker.
+type compileWriter struct {
+	output  *compileOutput
+	w       io.Writer
+	pending []byte
+}
+
+func (cw *compileWriter) Write(p []byte) (int, error) {
+	cw.pending = append(cw.pending, p...)
+	marker := []byte(cw.output.marker)
+
+	for {
+		// after the compile step, marker lines are output of the program
+		if cw.output.isFinished() {
+			if err := cw.forward(cw.pending); err != nil {
+				return 0, err
+			}
+			cw.pending = cw.pending[:0]
+			return len(p), nil
+		}
+
+		index := bytes.Index(cw.pending, marker)
+		if index == -1 {
+			keep := partialSuffix(cw.pending, marker)
+			if err := cw.forward(cw.pending[:len(cw.pending)-keep]); err != nil {
+				return 0, err
+			}
+			cw.pending = append(cw.pending[:0], cw.pending[len(cw.pending)-keep:]...)
+			return len(p), nil
+		}
+
+		end := bytes.IndexByte(cw.pending[index:], '\n')
+		if end == -1 {
+			// wait for the rest of the marker line
+			if err := cw.forward(cw.pending[:index]); err != nil {
+				return 0, err
+			}
+			cw.pending = append(cw.pending[:0], cw.pending[index:]...)
+			return len(p), nil
+		}
+
+		if err := cw.forward(cw.pending[:index]); err != nil {
+			return 0, err
+		}
+		cw.output.marked(strings.TrimRight(string(cw.pending[index+len(marker):index+end]), "\r"))
+		cw.pending = cw.pending[index+end+1:]
+	}
+}
+
+func (cw *compileWriter) forward(p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	cw.output.capture(p)
+	_, err := cw.w.Write(p)
+	return err
+}
+
+func (cw *compileWriter) flush() {
+	cw.forward(cw.pending)
+	cw.pending = nil
+}
+
+// partialSuffix returns the length of the longest suffix of p which is a prefix of marker.
+func partialSuffix(p, marker []byte) int {
+	n := len(marker) - 1
+	if n > len(p) {
+		n = len(p)
+	}
+
+	for ; n > 0; n-- {
+		if bytes.HasPrefix(marker, p[len(p)-n:]) {
+			return n
+		}
+	}
+
+	return 0
+}
+
+// compileError returns the error of a failed compile step.
+func compileError(compile *result.Compile) error {
+	message := fmt.Sprintf("compile failed with exit code %d", compile.ExitCode)
+	if compile.TimedOut {
+		message = "compile timed out"
+	}
+
+	return &cmderrors.CompileError{
+		ExitError: &cmderrors.ExitError{
+			Code:    compile.ExitCode,
+			Message: message,
+		},
+		Output:   compile.Output,
+		TimedOut: compile.TimedOut,
+	}
+}
@@ -0,0 +1,106 @@
+// Package language defines the presets running source code of a programming
+// language: the image with its toolchain, how the source file is named, and
+// the optional compile step before the program runs.
+package language
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-zoox/core-utils/safe"
+)
+
+const (
+	// Python runs the source with python3.
+	Python = "python"
+	// Node runs the source with node.
+	Node = "node"
+	// Go compiles the source with go build.
+	Go = "go"
+	// Java compiles the source, which declares the class Main, with javac.
+	Java = "java"
+	// Bash runs the source with bash.
+	Bash = "bash"
+)
+
+// DefaultCompileTimeout is the timeout of the compile step unless set by the preset or the config.
+const DefaultCompileTimeout = 30 * time.Second
+
+// Preset describes how the source code of a language is staged, compiled and run.
+type Preset struct {
+	// Name is the name the preset is registered with
+	Name string
+	// Image is the container image providing the toolchain
+	Image string
+	// FileName is the file name the source is staged as, e.g. Main.java
+	FileName string
+	// Compile is the shell command compiling the staged source in its directory,
+	// empty for interpreted languages
+	Compile string
+	// CompileTimeout is the timeout of Compile, default: DefaultCompileTimeout
+	CompileTimeout time.Duration
+	// Run is the shell command running the program in the directory of the source
+	Run string
+}
+
+// ErrPresetNotFound is the error returned when a preset is not found.
+var ErrPresetNotFound = errors.New("language preset not found")
+
+var presets = safe.NewMap[string, *Preset]()
+
+// Register registers a preset, replacing an existing one with the same name.
+func Register(name string, preset *Preset) error {
+	return presets.Set(name, preset)
+}
+
+// Get gets a copy of a preset.
+func Get(name string) (*Preset, error) {
+	preset := presets.Get(name)
+	if preset == nil {
+		return nil, ErrPresetNotFound
+	}
+
+	p := *preset
+	p.Name = name
+	if p.Compile != "" && p.CompileTimeout == 0 {
+		p.CompileTimeout = DefaultCompileTimeout
+	}
+	return &p, nil
+}
+
+func init() {
+	Register(Python, &Preset{
+		Image:    "python:3.12-alpine",
+		FileName: "main.py",
+		Run:      "python3 main.py",
+	})
+
+	Register(Node, &Preset{
+		Image:    "node:20-alpine",
+		FileName: "main.js",
+		Run:      "node main.js",
+	})
+
+	// the build cache lives next to the source, as the root filesystem may be read-only
+	Register(Go, &Preset{
+		Image:          "golang:1.22-alpine",
+		FileName:       "main.go",
+		Compile:        `CGO_ENABLED=0 GOCACHE="$PWD/.cache" GOPATH="$PWD/.go" GOTMPDIR="$PWD" go build -o main main.go`,
+		CompileTimeout: 60 * time.Second,
+		Run:            "./main",
+	})
+
+	Register(Java, &Preset{
+		Image:          "eclipse-temurin:21-jdk-alpine",
+		FileName:       "Main.java",
+		Compile:        "javac Main.java",
+		CompileTimeout: 60 * time.Second,
+		Run:            "java -cp . Main",
+	})
+
+	Register(Bash, &Preset{
+		Image:    "bash:5",
+		FileName: "main.sh",
+		Run:      "bash main.sh",
+	})
+}
@@ -0,0 +1,41 @@
+package language
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGet_Builtin(t *testing.T) {
+	for _, name := range []string{Python, Node, Go, Java, Bash} {
+		preset, err := Get(name)
+		if err != nil {
+			t.Fatalf("Get %s: %v", name, err)
+		}
+		if preset.Name != name || preset.Image == "" || preset.FileName == "" || preset.Run == "" {
+			t.Errorf("incomplete preset %s: %+v", name, preset)
+		}
+	}
+
+	java, _ := Get(Java)
+	if java.Compile == "" || java.CompileTimeout == 0 {
+		t.Errorf("expected java to compile with a timeout: %+v", java)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register("lua-test", &Preset{Image: "lua", FileName: "main.lua", Compile: "luac -p main.lua", Run: "lua main.lua"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	preset, err := Get("lua-test")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if preset.CompileTimeout != DefaultCompileTimeout {
+		t.Errorf("expected default compile timeout, got %s", preset.CompileTimeout)
+	}
+
+	if _, err := Get("unknown"); !errors.Is(err, ErrPresetNotFound) {
+		t.Errorf("expected ErrPresetNotFound, got %v", err)
+	}
+}
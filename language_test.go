@@ -0,0 +1,130 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	cmderrors "github.com/go-zoox/command/errors"
+)
+
+func init() {
+	RegisterLanguage("test-sh", &LanguagePreset{
+		FileName: "main.sh",
+		Compile:  "sh -n main.sh",
+		Run:      "sh main.sh",
+	})
+}
+
+func TestLanguage_Run(t *testing.T) {
+	cmd, err := New(&Config{
+		Language: "test-sh",
+		Script:   "read name\necho \"hello $name\"\n",
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.SetStdin(strings.NewReader("zero\n"))
+	cmd.SetStdout(stdout)
+	cmd.SetStderr(stderr)
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	if stdout.String() != "hello zero\n" {
+		t.Errorf("unexpected stdout: %q", stdout.String())
+	}
+	if strings.Contains(stderr.String(), "go-zoox-command-compile-") {
+		t.Errorf("expected the compile markers to be removed, got %q", stderr.String())
+	}
+
+	compile := cmd.Result().Compile
+	if compile == nil || compile.ExitCode != 0 || compile.TimedOut {
+		t.Errorf("unexpected compile result: %+v", compile)
+	}
+}
+
+func TestLanguage_CompileError(t *testing.T) {
+	cmd, err := New(&Config{
+		Language: "test-sh",
+		Script:   "if then fi\n",
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	cmd.SetStdout(&bytes.Buffer{})
+	cmd.SetStderr(&bytes.Buffer{})
+
+	err = cmd.Run()
+	var compileErr *cmderrors.CompileError
+	if !errors.As(err, &compileErr) {
+		t.Fatalf("expected compile error, got %v", err)
+	}
+	if compileErr.Code == 0 || compileErr.Output == "" {
+		t.Errorf("unexpected compile error: %+v", compileErr)
+	}
+
+	compile := cmd.Result().Compile
+	if compile == nil || compile.ExitCode == 0 || compile.Output != compileErr.Output {
+		t.Errorf("unexpected compile result: %+v", compile)
+	}
+}
+
+func TestLanguage_Invalid(t *testing.T) {
+	if _, err := New(&Config{Language: "cobol", Script: "x"}); err == nil {
+		t.Error("expected error for unknown language")
+	}
+
+	if _, err := New(&Config{Language: "python"}); err == nil || !strings.Contains(err.Error(), "requires the source code") {
+		t.Errorf("expected missing source error, got %v", err)
+	}
+
+	if _, err := New(&Config{Language: "python", Script: "print(1)", TTY: true}); err == nil || !strings.Contains(err.Error(), "does not support TTY") {
+		t.Errorf("expected tty error, got %v", err)
+	}
+}
+
+func TestCompileWriter_SplitMarker(t *testing.T) {
+	output := &compileOutput{marker: "marker-1"}
+	out := &bytes.Buffer{}
+	w := output.wrap(out)
+
+	for _, chunk := range []string{"before\nmark", "er-1:begin\nerror: x\nmarker-", "1:end:2", "\nafter\n"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	compile := output.finish()
+	if out.String() != "before\nerror: x\nafter\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	if compile == nil || compile.ExitCode != 2 || compile.Output != "error: x\n" {
+		t.Errorf("unexpected compile result: %+v", compile)
+	}
+}
+
+func TestCompileWriter_IgnoresMarkersAfterEnd(t *testing.T) {
+	output := &compileOutput{marker: "marker-1"}
+	out := &bytes.Buffer{}
+	w := output.wrap(out)
+
+	// the program prints forged markers after the compile step
+	for _, chunk := range []string{"marker-1:begin\nok\nmarker-1:end:0\n", "marker-1:begin\nforged\nmarker-1:end:1\n"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	compile := output.finish()
+	if out.String() != "ok\nmarker-1:begin\nforged\nmarker-1:end:1\n" {
+		t.Errorf("expected the forged markers in the output, got %q", out.String())
+	}
+	if compile == nil || compile.ExitCode != 0 || compile.Output != "ok\n" {
+		t.Errorf("expected the first compile result, got %+v", compile)
+	}
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/engine/dind"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/k8s"
+	"github.com/go-zoox/command/engine/podman"
+)
+
+// Mount is a bind mount, volume or tmpfs mount of a container.
+type Mount = config.Mount
+
+// checkMounts validates the mounts for the engine and sets their default types.
+func checkMounts(cfg *Config) error {
+	switch cfg.Engine {
+	case docker.Name, podman.Name, dind.Name, k8s.Name:
+	default:
+		return fmt.Errorf("mounts are only supported by docker, podman, dind and k8s engines, but got: %s", cfg.Engine)
+	}
+
+	// the mounts of the caller are not modified
+	mounts := make([]Mount, len(cfg.Mounts))
+	for index, m := range cfg.Mounts {
+		if !path.IsAbs(m.Target) {
+			return fmt.Errorf("mount target must be an absolute path, but got: %s", m.Target)
+		}
+
+		if m.Type == "" {
+			m.Type = config.MountVolume
+			if path.IsAbs(m.Source) {
+				m.Type = config.MountBind
+			}
+		}
+
+		switch m.Type {
+		case config.MountBind:
+			if !path.IsAbs(m.Source) {
+				return fmt.Errorf("bind mount source must be an absolute path, but got: %s", m.Source)
+			}
+		case config.MountVolume:
+		case config.MountTmpfs:
+			if m.Source != "" {
+				return fmt.Errorf("tmpfs mount %s cannot have a source", m.Target)
+			}
+			if m.TmpfsMode != 0 && cfg.Engine == k8s.Name {
+				return fmt.Errorf("tmpfs mode is not supported by engine: %s", cfg.Engine)
+			}
+		default:
+			return fmt.Errorf("invalid mount type: %s, available: bind, volume, tmpfs", m.Type)
+		}
+
+		if m.Type != config.MountTmpfs && (m.TmpfsSize != 0 || m.TmpfsMode != 0) {
+			return fmt.Errorf("tmpfs options are only supported by tmpfs mounts, but %s is a %s mount", m.Target, m.Type)
+		}
+
+		mounts[index] = m
+	}
+
+	cfg.Mounts = mounts
+	return nil
+}
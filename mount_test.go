@@ -0,0 +1,48 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckMounts(t *testing.T) {
+	mounts := []Mount{
+		{Source: "/data", Target: "/data"},
+		{Source: "cache", Target: "/cache"},
+		{Type: "tmpfs", Target: "/tmp", TmpfsSize: 1 << 20},
+	}
+	cfg := &Config{Engine: "docker", Mounts: mounts}
+	if err := checkMounts(cfg); err != nil {
+		t.Fatalf("checkMounts: %v", err)
+	}
+
+	for index, expected := range []string{"bind", "volume", "tmpfs"} {
+		if cfg.Mounts[index].Type != expected {
+			t.Errorf("expected mount %d to be %s, got %s", index, expected, cfg.Mounts[index].Type)
+		}
+	}
+	if mounts[0].Type != "" {
+		t.Error("expected the mounts of the caller to be unchanged")
+	}
+}
+
+func TestCheckMounts_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		engine string
+		mount  Mount
+		err    string
+	}{
+		{"host", Mount{Source: "/data", Target: "/data"}, "only supported by"},
+		{"docker", Mount{Source: "/data", Target: "data"}, "absolute path"},
+		{"docker", Mount{Type: "bind", Source: "data", Target: "/data"}, "bind mount source"},
+		{"docker", Mount{Type: "tmpfs", Source: "x", Target: "/tmp"}, "cannot have a source"},
+		{"docker", Mount{Source: "cache", Target: "/cache", TmpfsSize: 1}, "tmpfs options"},
+		{"docker", Mount{Type: "nfs", Target: "/mnt"}, "invalid mount type"},
+		{"k8s", Mount{Type: "tmpfs", Target: "/tmp", TmpfsMode: 0700}, "tmpfs mode"},
+	} {
+		err := checkMounts(&Config{Engine: tc.engine, Mounts: []Mount{tc.mount}})
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s %+v: expected error containing %q, got %v", tc.engine, tc.mount, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/namespace"
+	"github.com/go-zoox/command/engine/podman"
+	"github.com/go-zoox/command/result"
+)
+
+// Port is a published port of a container.
+type Port = result.Port
+
+// isNetworkingUsed reports whether the config uses the container networking options.
+func isNetworkingUsed(cfg *Config) bool {
+	return len(cfg.Networks) != 0 || len(cfg.NetworkAliases) != 0 || cfg.Hostname != "" ||
+		len(cfg.Ports) != 0 || len(cfg.DNS) != 0 || len(cfg.ExtraHosts) != 0
+}
+
+// checkNetworking validates the container networking options for the engine.
+func checkNetworking(cfg *Config) error {
+	switch cfg.Engine {
+	case docker.Name, podman.Name:
+	case namespace.Name:
+		// the uts namespace has its own hostname, the other options need a container network
+		if len(cfg.Networks) != 0 || len(cfg.NetworkAliases) != 0 || len(cfg.Ports) != 0 || len(cfg.DNS) != 0 || len(cfg.ExtraHosts) != 0 {
+			return fmt.Errorf("only hostname of the networking options is supported by the namespace engine")
+		}
+		return nil
+	default:
+		return fmt.Errorf("networking options are only supported by docker and podman engines, but got: %s", cfg.Engine)
+	}
+
+	if cfg.DisableNetwork && (len(cfg.Ports) != 0 || len(cfg.Networks) != 0) {
+		return errors.New("ports and networks cannot be combined with disabled network")
+	}
+
+	if _, _, err := nat.ParsePortSpecs(cfg.Ports); err != nil {
+		return fmt.Errorf("invalid ports: %w", err)
+	}
+
+	return nil
+}
+
+// Ports returns the published ports of the container with their bound host ports,
+// once the command has started.
+func (c *command) Ports() ([]Port, error) {
+	if c.result != nil {
+		return c.result.Ports, nil
+	}
+
+	if c.startedAt.IsZero() {
+		return nil, errors.New("command is not started")
+	}
+
+	reader, ok := c.engine.(engine.PortReader)
+	if !ok {
+		return nil, fmt.Errorf("ports are not supported by engine: %s", c.cfg.Engine)
+	}
+
+	return reader.Ports(), nil
+}
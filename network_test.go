@@ -0,0 +1,48 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckNetworking(t *testing.T) {
+	if err := checkNetworking(&Config{Engine: "docker", Ports: []string{"8080:80", "127.0.0.1::53/udp"}}); err != nil {
+		t.Errorf("checkNetworking: %v", err)
+	}
+
+	for _, tc := range []struct {
+		cfg *Config
+		err string
+	}{
+		{&Config{Engine: "host", Ports: []string{"80"}}, "only supported by docker and podman"},
+		{&Config{Engine: "docker", Ports: []string{"http"}}, "invalid ports"},
+		{&Config{Engine: "docker", Ports: []string{"80"}, DisableNetwork: true}, "disabled network"},
+		{&Config{Engine: "podman", Networks: []string{"backend"}, DisableNetwork: true}, "disabled network"},
+	} {
+		if err := checkNetworking(tc.cfg); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("expected error containing %q, got %v", tc.err, err)
+		}
+	}
+
+	if _, err := New(&Config{Command: "echo", Hostname: "box"}); err == nil {
+		t.Error("expected error for hostname on the host engine")
+	}
+}
+
+func TestPorts_NotStarted(t *testing.T) {
+	cmd, err := New(&Config{Command: "echo"})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+
+	if _, err := cmd.Ports(); err == nil {
+		t.Error("expected error before start")
+	}
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if ports, err := cmd.Ports(); err != nil || len(ports) != 0 {
+		t.Errorf("expected no ports, got %v, %v", ports, err)
+	}
+}
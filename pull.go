@@ -0,0 +1,47 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/engine/dind"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/k8s"
+	"github.com/go-zoox/command/engine/podman"
+)
+
+// RegistryAuth is the credential of an image registry.
+type RegistryAuth = config.RegistryAuth
+
+// checkPull validates the image pull policy and registry credentials for the engine.
+func checkPull(cfg *Config) error {
+	if cfg.PullPolicy != "" {
+		switch cfg.PullPolicy {
+		case config.PullAlways, config.PullIfNotPresent, config.PullNever:
+		default:
+			return fmt.Errorf("invalid pull policy: %s, available: Always, IfNotPresent, Never", cfg.PullPolicy)
+		}
+
+		switch cfg.Engine {
+		case docker.Name, podman.Name, dind.Name, k8s.Name:
+		default:
+			return fmt.Errorf("pull policy is only supported by docker, podman, dind and k8s engines, but got: %s", cfg.Engine)
+		}
+	}
+
+	if len(cfg.RegistryAuths) != 0 {
+		switch cfg.Engine {
+		case docker.Name, podman.Name, dind.Name:
+		default:
+			return fmt.Errorf("registry auths are only supported by docker, podman and dind engines, but got: %s", cfg.Engine)
+		}
+
+		for _, auth := range cfg.RegistryAuths {
+			if auth.Registry == "" {
+				return fmt.Errorf("registry auth requires a registry")
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+// Package pull implements the image pull policy and resolves the credentials
+// of image pulls: the configured registry auths first, then the docker config
+// file (~/.docker/config.json) with its credential helpers.
+package pull
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/go-zoox/command/config"
+)
+
+// Image pull policies.
+const (
+	Always       = config.PullAlways
+	IfNotPresent = config.PullIfNotPresent
+	Never        = config.PullNever
+)
+
+// dockerHub is the registry of the images without registry host.
+const dockerHub = "docker.io"
+
+// dockerHubServer is the server address of Docker Hub in the docker config file and credential helpers.
+const dockerHubServer = "https://index.docker.io/v1/"
+
+// ErrNotPresent is returned when an image is missing locally and must not be pulled.
+var ErrNotPresent = errors.New("image not present locally and pull policy is Never")
+
+// IsRequired reports whether the image is pulled under the policy. It fails
+// with ErrNotPresent if the image is missing and must not be pulled.
+func IsRequired(policy string, isPresent bool) (bool, error) {
+	switch policy {
+	case Always:
+		return true, nil
+	case Never:
+		if !isPresent {
+			return false, ErrNotPresent
+		}
+		return false, nil
+	default:
+		return !isPresent, nil
+	}
+}
+
+// Registry returns the registry host of the image, docker.io for Docker Hub.
+func Registry(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid image %s: %w", image, err)
+	}
+
+	return reference.Domain(named), nil
+}
+
+// Auth returns the encoded credential (X-Registry-Auth) of the image pull,
+// empty if there is none.
+func Auth(image string, auths []config.RegistryAuth) (string, error) {
+	host, err := Registry(image)
+	if err != nil {
+		return "", err
+	}
+
+	auth, err := Lookup(host, auths)
+	if err != nil || auth == nil {
+		return "", err
+	}
+
+	return registry.EncodeAuthConfig(*auth)
+}
+
+// Lookup returns the credential of the registry host, nil if there is none.
+func Lookup(host string, auths []config.RegistryAuth) (*registry.AuthConfig, error) {
+	host = normalize(host)
+	for _, auth := range auths {
+		if normalize(auth.Registry) == host {
+			return &registry.AuthConfig{
+				Username:      auth.Username,
+				Password:      auth.Password,
+				IdentityToken: auth.IdentityToken,
+				ServerAddress: serverAddress(host),
+			}, nil
+		}
+	}
+
+	cfg, err := loadDockerConfig()
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+
+	return cfg.lookup(host)
+}
+
+// normalize returns the host of a registry address, e.g. https://index.docker.io/v1/ is docker.io.
+func normalize(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return dockerHub
+	}
+
+	return host
+}
+
+// serverAddress returns the address of the registry host known to the config file and credential helpers.
+func serverAddress(host string) string {
+	if host == dockerHub {
+		return dockerHubServer
+	}
+
+	return host
+}
+
+// dockerConfig is the part of the docker config file holding credentials.
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth          string `json:"auth"`
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		IdentityToken string `json:"identitytoken"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// loadDockerConfig loads $DOCKER_CONFIG/config.json or ~/.docker/config.json, nil if missing.
+func loadDockerConfig() (*dockerConfig, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	file := filepath.Join(dir, "config.json")
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cfg := &dockerConfig{}
+	if err := json.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("invalid docker config %s: %w", file, err)
+	}
+
+	return cfg, nil
+}
+
+// lookup returns the credential of the registry host from its credential helper,
+// the credential store or the auths of the config file.
+func (c *dockerConfig) lookup(host string) (*registry.AuthConfig, error) {
+	helper := c.CredHelpers[host]
+	if helper == "" {
+		helper = c.CredHelpers[serverAddress(host)]
+	}
+	if helper == "" {
+		helper = c.CredsStore
+	}
+	if helper != "" {
+		return helperGet(helper, serverAddress(host))
+	}
+
+	for server, entry := range c.Auths {
+		if normalize(server) != host {
+			continue
+		}
+
+		auth := &registry.AuthConfig{
+			Username:      entry.Username,
+			Password:      entry.Password,
+			IdentityToken: entry.IdentityToken,
+			ServerAddress: server,
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of %s in docker config: %w", server, err)
+			}
+			auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+		}
+
+		return auth, nil
+	}
+
+	return nil, nil
+}
+
+// helperGet gets the credential of the server from the credential helper
+// docker-credential-<helper>, nil if it has none.
+func helperGet(helper, server string) (*registry.AuthConfig, error) {
+	stdout := &bytes.Buffer{}
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(server)
+	cmd.Stdout = stdout
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(stdout.String(), "credentials not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("credential helper %s: %w", helper, err)
+	}
+
+	credential := struct {
+		Username string
+		Secret   string
+	}{}
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+		return nil, fmt.Errorf("credential helper %s: invalid output: %w", helper, err)
+	}
+
+	auth := &registry.AuthConfig{ServerAddress: server}
+	// helpers store identity tokens with this user name
+	if credential.Username == "<token>" {
+		auth.IdentityToken = credential.Secret
+	} else {
+		auth.Username, auth.Password = credential.Username, credential.Secret
+	}
+
+	return auth, nil
+}
+
+// AuthConfigs returns the configured credentials by server address, as image
+// builds take them for the registries of their base images.
+func AuthConfigs(auths []config.RegistryAuth) map[string]registry.AuthConfig {
+	configs := map[string]registry.AuthConfig{}
+	for _, auth := range auths {
+		server := serverAddress(normalize(auth.Registry))
+		if _, ok := configs[server]; ok {
+			continue
+		}
+
+		configs[server] = registry.AuthConfig{
+			Username:      auth.Username,
+			Password:      auth.Password,
+			IdentityToken: auth.IdentityToken,
+			ServerAddress: server,
+		}
+	}
+
+	return configs
+}
@@ -0,0 +1,123 @@
+package pull
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/go-zoox/command/config"
+)
+
+func TestIsRequired(t *testing.T) {
+	for _, tc := range []struct {
+		policy    string
+		isPresent bool
+		required  bool
+		err       error
+	}{
+		{"", true, false, nil},
+		{"", false, true, nil},
+		{IfNotPresent, false, true, nil},
+		{Always, true, true, nil},
+		{Never, true, false, nil},
+		{Never, false, false, ErrNotPresent},
+	} {
+		required, err := IsRequired(tc.policy, tc.isPresent)
+		if required != tc.required || !errors.Is(err, tc.err) {
+			t.Errorf("IsRequired(%q, %v) = %v, %v", tc.policy, tc.isPresent, required, err)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	for image, expected := range map[string]string{
+		"alpine":                           "docker.io",
+		"library/alpine:3":                 "docker.io",
+		"ghcr.io/go-zoox/command:v1":       "ghcr.io",
+		"localhost:5000/app@sha256:" + sha: "localhost:5000",
+	} {
+		if host, err := Registry(image); err != nil || host != expected {
+			t.Errorf("Registry(%s) = %s, %v, expected %s", image, host, err, expected)
+		}
+	}
+}
+
+const sha = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestLookup_Configured(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	auth, err := Lookup("docker.io", []config.RegistryAuth{
+		{Registry: "ghcr.io", Username: "other"},
+		{Registry: "https://index.docker.io/v1/", Username: "user", Password: "secret"},
+	})
+	if err != nil || auth == nil || auth.Username != "user" || auth.ServerAddress != dockerHubServer {
+		t.Fatalf("unexpected auth: %+v, %v", auth, err)
+	}
+
+	if auth, err := Lookup("quay.io", nil); err != nil || auth != nil {
+		t.Errorf("expected no auth, got %+v, %v", auth, err)
+	}
+}
+
+func TestLookup_DockerConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("user:p@ss:word"))
+	content := `{"auths": {"https://index.docker.io/v1/": {"auth": "` + encoded + `"}, "ghcr.io": {"identitytoken": "token"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := Lookup("docker.io", nil)
+	if err != nil || auth == nil || auth.Username != "user" || auth.Password != "p@ss:word" {
+		t.Fatalf("unexpected auth: %+v, %v", auth, err)
+	}
+
+	auth, err = Lookup("ghcr.io", nil)
+	if err != nil || auth == nil || auth.IdentityToken != "token" {
+		t.Fatalf("unexpected auth: %+v, %v", auth, err)
+	}
+
+	encodedAuth, err := Auth("ghcr.io/go-zoox/command", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := registry.DecodeAuthConfig(encodedAuth)
+	if err != nil || decoded.IdentityToken != "token" {
+		t.Errorf("unexpected encoded auth: %+v, %v", decoded, err)
+	}
+}
+
+func TestLookup_CredentialHelper(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	helper := `#!/bin/sh
+read server
+case "$server" in
+  registry.example.com) echo '{"ServerURL": "registry.example.com", "Username": "helper", "Secret": "s3cret"}' ;;
+  *) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker-credential-fake"), []byte(helper), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"credsStore": "fake"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := Lookup("registry.example.com", nil)
+	if err != nil || auth == nil || auth.Username != "helper" || auth.Password != "s3cret" {
+		t.Fatalf("unexpected auth: %+v, %v", auth, err)
+	}
+
+	if auth, err := Lookup("quay.io", nil); err != nil || auth != nil {
+		t.Errorf("expected no auth, got %+v, %v", auth, err)
+	}
+}
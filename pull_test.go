@@ -0,0 +1,29 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPull(t *testing.T) {
+	if err := checkPull(&Config{Engine: "k8s", PullPolicy: "Never"}); err != nil {
+		t.Errorf("checkPull: %v", err)
+	}
+	if err := checkPull(&Config{Engine: "podman", RegistryAuths: []RegistryAuth{{Registry: "ghcr.io", Username: "u", Password: "p"}}}); err != nil {
+		t.Errorf("checkPull: %v", err)
+	}
+
+	for _, tc := range []struct {
+		cfg *Config
+		err string
+	}{
+		{&Config{Engine: "docker", PullPolicy: "always"}, "invalid pull policy"},
+		{&Config{Engine: "host", PullPolicy: "Always"}, "only supported by docker, podman, dind and k8s"},
+		{&Config{Engine: "k8s", RegistryAuths: []RegistryAuth{{Registry: "ghcr.io"}}}, "only supported by docker, podman and dind"},
+		{&Config{Engine: "docker", RegistryAuths: []RegistryAuth{{Username: "u"}}}, "requires a registry"},
+	} {
+		if err := checkPull(tc.cfg); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("expected error containing %q, got %v", tc.err, err)
+		}
+	}
+}
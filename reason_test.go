@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cmderrors "github.com/go-zoox/command/errors"
+)
+
+func TestReason_Signal(t *testing.T) {
+	cmd, err := New(&Config{Command: "kill -TERM $$"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	r := cmd.Result()
+	if r.Reason != cmderrors.ReasonSignal || r.Signal != "SIGTERM" {
+		t.Errorf("expected reason Signal with SIGTERM, got %q %q", r.Reason, r.Signal)
+	}
+}
+
+func TestReason_Timeout(t *testing.T) {
+	cmd, err := New(&Config{Command: "sleep 5", Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	err = cmd.Run()
+	exitErr, ok := err.(*cmderrors.ExitError)
+	if !ok || exitErr.Reason != cmderrors.ReasonTimeout {
+		t.Fatalf("expected an exit error with reason Timeout, got %v", err)
+	}
+	if r := cmd.Result(); r.Reason != cmderrors.ReasonTimeout || r.ExitCode != -1 {
+		t.Errorf("expected reason Timeout with exit code -1, got %q %d", r.Reason, r.ExitCode)
+	}
+}
+
+func TestTerminationReason(t *testing.T) {
+	exitErr := &cmderrors.ExitError{Code: 137}
+	terminationReason(context.Background(), exitErr)
+	if exitErr.Reason != cmderrors.ReasonSignal || exitErr.Signal != "SIGKILL" {
+		t.Errorf("expected reason Signal with SIGKILL, got %q %q", exitErr.Reason, exitErr.Signal)
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	exitErr = &cmderrors.ExitError{Code: 137}
+	terminationReason(ctx, exitErr)
+	if exitErr.Reason != cmderrors.ReasonDeadlineExceeded {
+		t.Errorf("expected reason DeadlineExceeded, got %q", exitErr.Reason)
+	}
+
+	exitErr = &cmderrors.ExitError{Code: 1}
+	terminationReason(context.Background(), exitErr)
+	if exitErr.Reason != "" {
+		t.Errorf("expected no reason, got %q", exitErr.Reason)
+	}
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/k8s"
+	"github.com/go-zoox/command/engine/podman"
+)
+
+// Ulimit is a resource limit of the processes in a container.
+type Ulimit = config.Ulimit
+
+// cpuSetRe matches the CPU lists, e.g. 0-3 or 0,2,4-7.
+var cpuSetRe = regexp.MustCompile(`^\d+(-\d+)?(,\d+(-\d+)?)*$`)
+
+// ulimitNames are the names of the limits a container accepts.
+var ulimitNames = map[string]bool{
+	"core": true, "cpu": true, "data": true, "fsize": true, "locks": true,
+	"memlock": true, "msgqueue": true, "nice": true, "nofile": true, "nproc": true,
+	"rss": true, "rtprio": true, "rttime": true, "sigpending": true, "stack": true,
+}
+
+// isResourcesUsed reports whether the container resource controls are configured.
+func isResourcesUsed(cfg *Config) bool {
+	return cfg.MemorySwap != 0 || cfg.MemoryReservation != 0 || cfg.CPUSet != "" ||
+		cfg.ShmSize != 0 || len(cfg.Ulimits) != 0 || cfg.BlkioWeight != 0 || cfg.StorageSize != 0
+}
+
+// checkResources validates the container resource controls for the engine.
+// k8s has no equivalent of swap, CPU pinning, ulimits and block I/O weight per pod.
+func checkResources(cfg *Config) error {
+	switch cfg.Engine {
+	case docker.Name, podman.Name:
+	case k8s.Name:
+		if cfg.MemorySwap != 0 || cfg.CPUSet != "" || len(cfg.Ulimits) != 0 || cfg.BlkioWeight != 0 {
+			return fmt.Errorf("memory swap, cpu set, ulimits and blkio weight are not supported by the k8s engine")
+		}
+	default:
+		return fmt.Errorf("resource controls are only supported by docker, podman and k8s engines, but got: %s", cfg.Engine)
+	}
+
+	if cfg.MemorySwap < -1 {
+		return fmt.Errorf("invalid memory swap: %d, must be -1 (unlimited) or a size", cfg.MemorySwap)
+	}
+	if cfg.MemorySwap > 0 && cfg.MemorySwap < cfg.Memory {
+		return fmt.Errorf("memory swap (%d MB) must not be less than memory (%d MB), as it includes the memory", cfg.MemorySwap, cfg.Memory)
+	}
+	if cfg.MemorySwap != 0 && cfg.Memory == 0 {
+		return fmt.Errorf("memory swap requires a memory limit")
+	}
+
+	if cfg.MemoryReservation < 0 {
+		return fmt.Errorf("invalid memory reservation: %d", cfg.MemoryReservation)
+	}
+	if cfg.Memory != 0 && cfg.MemoryReservation > cfg.Memory {
+		return fmt.Errorf("memory reservation (%d MB) must not be greater than memory (%d MB)", cfg.MemoryReservation, cfg.Memory)
+	}
+
+	if cfg.CPUSet != "" && !cpuSetRe.MatchString(cfg.CPUSet) {
+		return fmt.Errorf("invalid cpu set: %s, e.g. 0-3 or 0,2", cfg.CPUSet)
+	}
+
+	if cfg.ShmSize < 0 {
+		return fmt.Errorf("invalid shm size: %d", cfg.ShmSize)
+	}
+
+	for _, ulimit := range cfg.Ulimits {
+		if !ulimitNames[ulimit.Name] {
+			return fmt.Errorf("invalid ulimit: %s", ulimit.Name)
+		}
+		if ulimit.Soft > ulimit.Hard {
+			return fmt.Errorf("ulimit %s: soft limit %d is greater than hard limit %d", ulimit.Name, ulimit.Soft, ulimit.Hard)
+		}
+	}
+
+	if cfg.BlkioWeight != 0 && (cfg.BlkioWeight < 10 || cfg.BlkioWeight > 1000) {
+		return fmt.Errorf("invalid blkio weight: %d, must be from 10 to 1000", cfg.BlkioWeight)
+	}
+
+	if cfg.StorageSize < 0 {
+		return fmt.Errorf("invalid storage size: %d", cfg.StorageSize)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckResources(t *testing.T) {
+	cfg := &Config{
+		Engine:            "docker",
+		Memory:            512,
+		MemorySwap:        1024,
+		MemoryReservation: 256,
+		CPUSet:            "0-1,3",
+		ShmSize:           256,
+		Ulimits:           []Ulimit{{Name: "nofile", Soft: 1024, Hard: 4096}},
+		BlkioWeight:       500,
+		StorageSize:       1024,
+	}
+	if err := checkResources(cfg); err != nil {
+		t.Fatalf("checkResources: %v", err)
+	}
+
+	if err := checkResources(&Config{Engine: "k8s", MemoryReservation: 128, ShmSize: 64, StorageSize: 1024}); err != nil {
+		t.Fatalf("checkResources k8s: %v", err)
+	}
+}
+
+func TestCheckResources_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		cfg *Config
+		err string
+	}{
+		{&Config{Engine: "host", ShmSize: 64}, "only supported by"},
+		{&Config{Engine: "k8s", CPUSet: "0"}, "not supported by the k8s engine"},
+		{&Config{Engine: "docker", MemorySwap: 1024}, "requires a memory limit"},
+		{&Config{Engine: "docker", Memory: 512, MemorySwap: 256}, "must not be less than memory"},
+		{&Config{Engine: "docker", Memory: 512, MemorySwap: -2}, "invalid memory swap"},
+		{&Config{Engine: "docker", Memory: 256, MemoryReservation: 512}, "must not be greater than memory"},
+		{&Config{Engine: "docker", CPUSet: "0-"}, "invalid cpu set"},
+		{&Config{Engine: "podman", Ulimits: []Ulimit{{Name: "files", Soft: 1, Hard: 1}}}, "invalid ulimit"},
+		{&Config{Engine: "podman", Ulimits: []Ulimit{{Name: "nproc", Soft: 2, Hard: 1}}}, "greater than hard limit"},
+		{&Config{Engine: "docker", BlkioWeight: 5}, "invalid blkio weight"},
+		{&Config{Engine: "docker", StorageSize: -1}, "invalid storage size"},
+	} {
+		err := checkResources(tc.cfg)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%+v: expected error containing %q, got %v", tc.cfg, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-zoox/command/engine"
+	cmderrors "github.com/go-zoox/command/errors"
+	"github.com/go-zoox/command/result"
+)
+
+// Result is the outcome of a finished command.
+type Result = result.Result
+
+// Artifact is a file collected after the command exits.
+type Artifact = result.Artifact
+
+// Compile is the outcome of the compile step of a language preset.
+type Compile = result.Compile
+
+// Event is something which happened while the command ran.
+type Event = result.Event
+
+// Result returns the outcome of the command, nil until Wait returns.
+func (c *command) Result() *Result {
+	return c.result
+}
+
+// finish runs the post-exit work, i.e. collecting artifacts and removing
+// the environment kept for it, and records the result.
+func (c *command) finish(err error) error {
+	r := &Result{}
+
+	if c.compile != nil {
+		if r.Compile = c.compile.finish(); r.Compile != nil && r.Compile.ExitCode != 0 {
+			err = compileError(r.Compile)
+		}
+	}
+
+	var exitErr *cmderrors.ExitError
+	if err == nil {
+		r.ExitCode = 0
+	} else if errors.As(err, &exitErr) {
+		terminationReason(c.cfg.Context, exitErr)
+		r.ExitCode = exitErr.Code
+		r.OOMKilled = exitErr.OOMKilled
+		r.Reason = exitErr.Reason
+		r.Signal = exitErr.Signal
+	} else {
+		r.ExitCode = -1
+		if errors.Is(err, context.DeadlineExceeded) {
+			r.Reason = cmderrors.ReasonDeadlineExceeded
+		}
+	}
+
+	r.Stats = c.summarizeStats()
+
+	if reader, ok := c.engine.(engine.PortReader); ok {
+		r.Ports = reader.Ports()
+	}
+
+	if reader, ok := c.engine.(engine.EventReader); ok {
+		r.Events = reader.Events()
+	}
+
+	// artifacts are collected for failed commands too,
+	// but not if the command was cancelled or timed out.
+	var errx error
+	if len(c.cfg.Artifacts) != 0 && r.ExitCode != -1 {
+		r.Artifacts, errx = c.collectArtifacts(c.cfg.Context)
+	}
+
+	if c.cfg.ReportChanges {
+		var errc error
+		if r.Changes, errc = c.reportChanges(c.cfg.Context, r.ExitCode != -1); errc != nil && errx == nil {
+			errx = errc
+		}
+	}
+
+	if isContainerKept(c.cfg, r.ExitCode) {
+		r.Container = c.cfg.ID
+	} else if cleaner, ok := c.engine.(engine.Cleaner); ok && isAutoRemoveDisabled(c.cfg) {
+		if err := cleaner.Cleanup(); err != nil && errx == nil {
+			errx = err
+		}
+	}
+
+	c.result = r
+
+	if err != nil {
+		return err
+	}
+
+	return errx
+}
+
+// terminationReason completes the reason of the exit error the engine could not tell:
+// the deadline of the context which killed the command, or the signal reported by
+// the exit code 128+n.
+func terminationReason(ctx context.Context, exitErr *cmderrors.ExitError) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && exitErr.Reason != cmderrors.ReasonOOMKilled {
+		exitErr.Reason = cmderrors.ReasonDeadlineExceeded
+		return
+	}
+
+	if exitErr.Reason == "" {
+		if signal := cmderrors.ExitSignal(exitErr.Code); signal != 0 {
+			exitErr.Reason = cmderrors.ReasonSignal
+			exitErr.Signal = cmderrors.SignalName(signal)
+		}
+	}
+}
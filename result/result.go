@@ -0,0 +1,129 @@
+// Package result describes the outcome of a finished command.
+package result
+
+import "time"
+
+// Result is the outcome of a finished command.
+type Result struct {
+	// ExitCode is the exit code of the command, -1 if it did not exit normally
+	ExitCode int
+	// OOMKilled means the command was killed for exceeding its memory limit
+	OOMKilled bool
+	// Reason is why the command ended if it did not exit by itself: OOMKilled, Timeout,
+	// Signal, Evicted or DeadlineExceeded, empty otherwise
+	Reason string
+	// Signal is the name of the signal which killed the command, with the Signal reason
+	Signal string
+
+	// Artifacts is the manifest of the collected artifacts
+	Artifacts []Artifact
+
+	// Stats is the resource usage summary of the command
+	Stats *Stats
+
+	// Events are the notable events while the command ran, e.g. denied egress connections
+	Events []Event
+
+	// Changes are the filesystem changes made by the command, sorted by path
+	Changes []Change
+
+	// Compile is the outcome of the compile step of the language preset, nil without one
+	Compile *Compile
+
+	// Container is the name of the container kept after exit (KeepContainer), empty if it was removed
+	Container string
+
+	// Ports are the published ports of the container with their bound host ports
+	Ports []Port
+}
+
+// Port is a published port of a container.
+type Port struct {
+	// ContainerPort is the port in the container
+	ContainerPort int
+	// Protocol is tcp, udp or sctp
+	Protocol string
+	// HostIP is the host address the port is bound to, e.g. 0.0.0.0
+	HostIP string
+	// HostPort is the bound host port, which is random if it was not configured
+	HostPort int
+}
+
+// Compile is the outcome of the compile step of a language preset.
+type Compile struct {
+	// ExitCode is the exit code of the compiler
+	ExitCode int
+	// TimedOut means the compile step exceeded its timeout
+	TimedOut bool
+	// Output is the output of the compiler, truncated to 64 KiB
+	Output string
+}
+
+// Change kinds
+const (
+	// ChangeAdded means the path was created.
+	ChangeAdded = "added"
+	// ChangeModified means the content or metadata of the path changed.
+	ChangeModified = "modified"
+	// ChangeDeleted means the path was removed.
+	ChangeDeleted = "deleted"
+)
+
+// Change is a filesystem change made by the command.
+type Change struct {
+	// Path is the absolute path in the execution environment
+	Path string
+	// Kind is the kind of the change: added, modified or deleted
+	Kind string
+	// IsDir means the path is a directory
+	IsDir bool
+	// Size is the file size after the change, unit: byte, the size before for deleted files
+	Size int64
+	// SHA256 is the hex-encoded sha256 checksum of the regular file after the change
+	SHA256 string
+}
+
+// Event is something which happened while the command ran.
+type Event struct {
+	// Time is when the event happened
+	Time time.Time
+	// Type is the event type, e.g. egress.denied
+	Type string
+	// Target is what the event is about, e.g. the host:port of a denied connection
+	Target string
+	// Message describes the event
+	Message string
+}
+
+// Artifact is a file collected from the execution environment after the command exits.
+type Artifact struct {
+	// Path is the slash-separated path relative to the working directory
+	Path string
+	// Size is the file size, unit: byte
+	Size int64
+	// SHA256 is the hex-encoded sha256 checksum of the file content
+	SHA256 string
+}
+
+// Stats is the resource usage of a command. Fields an engine cannot measure are zero.
+type Stats struct {
+	// CPUTime is the total CPU time, i.e. UserTime + SystemTime
+	CPUTime time.Duration
+	// UserTime is the CPU time spent in user mode
+	UserTime time.Duration
+	// SystemTime is the CPU time spent in kernel mode
+	SystemTime time.Duration
+
+	// Memory is the current memory usage, unit: byte
+	Memory int64
+	// PeakMemory is the peak memory usage (RSS), unit: byte
+	PeakMemory int64
+
+	// BlockRead is the number of bytes read from block devices
+	BlockRead int64
+	// BlockWrite is the number of bytes written to block devices
+	BlockWrite int64
+
+	// WallTime is the elapsed real time since the command started
+	WallTime time.Duration
+}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/host"
+	"github.com/go-zoox/command/engine/k8s"
+	"github.com/go-zoox/command/engine/namespace"
+	"github.com/go-zoox/command/engine/podman"
+	"github.com/go-zoox/command/sandbox"
+)
+
+// SandboxProfile describes the security settings and resource defaults of sandbox mode.
+type SandboxProfile = sandbox.Profile
+
+// RegisterSandboxProfile registers a custom sandbox profile.
+func RegisterSandboxProfile(name string, profile *SandboxProfile) error {
+	return sandbox.Register(name, profile)
+}
+
+// applySandbox selects the engine and applies the defaults of the sandbox profile
+// to the settings which are not explicitly set.
+func applySandbox(cfg *Config) error {
+	switch cfg.Engine {
+	case "":
+		cfg.Engine = docker.Name
+	case docker.Name, namespace.Name, podman.Name, k8s.Name:
+	default:
+		return fmt.Errorf("sandbox mode requires docker, namespace, podman or k8s engine, but got: %s", cfg.Engine)
+	}
+
+	if cfg.SandboxProfile == "" {
+		cfg.SandboxProfile = sandbox.Standard
+	}
+
+	profile, err := sandbox.Get(cfg.SandboxProfile)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, cfg.SandboxProfile)
+	}
+
+	switch profile.Network {
+	case sandbox.NetworkNone:
+		cfg.DisableNetwork = true
+		cfg.Network = ""
+	case sandbox.NetworkOptIn:
+		if cfg.Network == "" {
+			cfg.DisableNetwork = true
+		}
+	}
+
+	// Force non-privileged in sandbox mode
+	cfg.Privileged = false
+
+	// the namespace engine cannot enforce these, so only the ones set
+	// explicitly are rejected by checkNamespace
+	if cfg.Engine != namespace.Name {
+		if cfg.User == "" {
+			cfg.User = profile.User
+		}
+		if cfg.PidsLimit == 0 {
+			cfg.PidsLimit = profile.PidsLimit
+		}
+		if cfg.Memory == 0 {
+			cfg.Memory = profile.Memory
+		}
+		if cfg.CPU == 0 {
+			cfg.CPU = profile.CPU
+		}
+	}
+	if cfg.Seccomp == "" && isSeccompSupported(cfg.Engine) {
+		cfg.Seccomp = profile.Seccomp
+	}
+
+	return nil
+}
+
+// sandboxProfile returns the sandbox profile of the config, nil without sandbox mode.
+func sandboxProfile(cfg *Config) *SandboxProfile {
+	if !cfg.Sandbox {
+		return nil
+	}
+
+	profile, err := sandbox.Get(cfg.SandboxProfile)
+	if err != nil {
+		return nil
+	}
+
+	return profile
+}
+
+// isSeccompSupported reports whether the engine can apply seccomp profiles.
+func isSeccompSupported(engine string) bool {
+	switch engine {
+	case host.Name, docker.Name, podman.Name, namespace.Name:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkNamespace rejects the settings the namespace engine cannot apply, as it
+// has neither cgroups nor other uids than the caller's.
+func checkNamespace(cfg *Config) error {
+	unsupported := []string{}
+	if cfg.User != "" {
+		unsupported = append(unsupported, "user")
+	}
+	if cfg.PidsLimit != 0 {
+		unsupported = append(unsupported, "pids limit")
+	}
+	if cfg.Memory != 0 {
+		unsupported = append(unsupported, "memory")
+	}
+	if cfg.CPU != 0 {
+		unsupported = append(unsupported, "cpu")
+	}
+
+	if len(unsupported) != 0 {
+		return fmt.Errorf("%s not supported by the namespace engine, use a sandbox profile without them", strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
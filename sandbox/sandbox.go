@@ -0,0 +1,109 @@
+// Package sandbox defines the profiles of sandbox mode. The built-in strict,
+// standard and permissive profiles can be complemented with custom ones.
+package sandbox
+
+import (
+	"errors"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/core-utils/safe"
+)
+
+// Profile describes the security settings and resource defaults of sandbox mode.
+type Profile = config.SandboxProfile
+
+const (
+	// Strict runs as nobody without capabilities, network and with tight limits.
+	Strict = "strict"
+	// Standard drops the dangerous capabilities, network is opt-in.
+	Standard = "standard"
+	// Permissive keeps a writable root filesystem and the network.
+	Permissive = "permissive"
+)
+
+// Network policies
+const (
+	// NetworkNone always disables the network.
+	NetworkNone = "none"
+	// NetworkOptIn disables the network unless Config.Network is set.
+	NetworkOptIn = "opt-in"
+	// NetworkAllow keeps the network as configured.
+	NetworkAllow = "allow"
+)
+
+// ErrProfileNotFound is the error returned when a profile is not found.
+var ErrProfileNotFound = errors.New("sandbox profile not found")
+
+var profiles = safe.NewMap[string, *Profile]()
+
+// Register registers a profile, replacing an existing one with the same name.
+func Register(name string, profile *Profile) error {
+	return profiles.Set(name, profile)
+}
+
+// Get gets a copy of a profile.
+func Get(name string) (*Profile, error) {
+	profile := profiles.Get(name)
+	if profile == nil {
+		return nil, ErrProfileNotFound
+	}
+
+	p := *profile
+	p.Name = name
+	return &p, nil
+}
+
+// capabilities are the capabilities kept by the standard profile.
+var capabilities = []string{
+	"CHOWN",
+	"DAC_OVERRIDE",
+	"FOWNER",
+	"FSETID",
+	"KILL",
+	"SETGID",
+	"SETUID",
+	"SETPCAP",
+	"NET_BIND_SERVICE",
+	"NET_RAW",
+	"SYS_CHROOT",
+	"MKNOD",
+	"AUDIT_WRITE",
+	"SETFCAP",
+}
+
+func init() {
+	Register(Strict, &Profile{
+		ReadOnlyRootfs: true,
+		Tmpfs: map[string]string{
+			"/tmp": "rw,noexec,nosuid,nodev,size=64m",
+		},
+		User:      "65534:65534",
+		PidsLimit: 64,
+		Network:   NetworkNone,
+		Memory:    256,
+		CPU:       0.5,
+		Seccomp:   "strict",
+	})
+
+	Register(Standard, &Profile{
+		Capabilities:   capabilities,
+		ReadOnlyRootfs: true,
+		Tmpfs: map[string]string{
+			"/tmp":     "rw,noexec,nosuid,size=100m",
+			"/var/tmp": "rw,noexec,nosuid,size=100m",
+		},
+		Network: NetworkOptIn,
+		Memory:  512,
+		CPU:     1.0,
+	})
+
+	Register(Permissive, &Profile{
+		Capabilities: capabilities,
+		Tmpfs: map[string]string{
+			"/tmp": "rw,nosuid,size=512m",
+		},
+		Network: NetworkAllow,
+		Memory:  2048,
+		CPU:     2.0,
+	})
+}
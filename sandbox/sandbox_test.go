@@ -0,0 +1,49 @@
+package sandbox
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGet_Builtin(t *testing.T) {
+	for _, name := range []string{Strict, Standard, Permissive} {
+		profile, err := Get(name)
+		if err != nil {
+			t.Fatalf("Get %s: %v", name, err)
+		}
+		if profile.Name != name {
+			t.Errorf("expected name %s, got %s", name, profile.Name)
+		}
+	}
+
+	standard, _ := Get(Standard)
+	if standard.Memory != 512 || standard.CPU != 1.0 || !standard.ReadOnlyRootfs || standard.Network != NetworkOptIn {
+		t.Errorf("unexpected standard profile: %+v", standard)
+	}
+}
+
+func TestGet_NotFound(t *testing.T) {
+	if _, err := Get("unknown"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+func TestRegister_Custom(t *testing.T) {
+	if err := Register("custom-test", &Profile{Memory: 128, Network: NetworkNone}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	profile, err := Get("custom-test")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if profile.Name != "custom-test" || profile.Memory != 128 {
+		t.Errorf("unexpected custom profile: %+v", profile)
+	}
+
+	// the registered profile is not changed through the copy
+	profile.Memory = 1
+	if again, _ := Get("custom-test"); again.Memory != 128 {
+		t.Errorf("expected registered profile unchanged, got %+v", again)
+	}
+}
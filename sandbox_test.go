@@ -0,0 +1,120 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/command/sandbox"
+)
+
+func TestSandboxProfile_Strict(t *testing.T) {
+	cfg := &Config{
+		Command:        "echo test",
+		Sandbox:        true,
+		SandboxProfile: sandbox.Strict,
+		Network:        "custom-network", // forced off by the strict profile
+	}
+
+	if err := prepare(cfg); err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+
+	if cfg.Engine != "docker" {
+		t.Errorf("expected engine docker, got %s", cfg.Engine)
+	}
+	if !cfg.DisableNetwork || cfg.Network != "" {
+		t.Errorf("expected network disabled, got DisableNetwork=%v Network=%q", cfg.DisableNetwork, cfg.Network)
+	}
+	if cfg.User != "65534:65534" || cfg.PidsLimit != 64 || cfg.Memory != 256 || cfg.CPU != 0.5 || cfg.Seccomp != "strict" {
+		t.Errorf("expected strict defaults, got User=%q PidsLimit=%d Memory=%d CPU=%f Seccomp=%q", cfg.User, cfg.PidsLimit, cfg.Memory, cfg.CPU, cfg.Seccomp)
+	}
+}
+
+func TestSandboxProfile_Custom(t *testing.T) {
+	if err := RegisterSandboxProfile("custom-command-test", &SandboxProfile{
+		Network: sandbox.NetworkAllow,
+		Memory:  64,
+	}); err != nil {
+		t.Fatalf("RegisterSandboxProfile: %v", err)
+	}
+
+	cfg := &Config{
+		Command:        "echo test",
+		Sandbox:        true,
+		SandboxProfile: "custom-command-test",
+		CPU:            3, // explicit settings win
+	}
+	if err := prepare(cfg); err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+
+	if cfg.DisableNetwork || cfg.Memory != 64 || cfg.CPU != 3 {
+		t.Errorf("unexpected settings: DisableNetwork=%v Memory=%d CPU=%f", cfg.DisableNetwork, cfg.Memory, cfg.CPU)
+	}
+}
+
+func TestSandboxProfile_NotFound(t *testing.T) {
+	err := prepare(&Config{Command: "echo test", Sandbox: true, SandboxProfile: "unknown"})
+	if !errors.Is(err, sandbox.ErrProfileNotFound) {
+		t.Errorf("expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+func TestSandboxMode_K8s(t *testing.T) {
+	cfg := &Config{
+		Command: "echo test",
+		Sandbox: true,
+		Engine:  "k8s",
+	}
+
+	if err := prepare(cfg); err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+
+	if !cfg.DisableNetwork {
+		t.Error("expected network disabled by the standard profile")
+	}
+	if cfg.Seccomp != "" {
+		t.Errorf("expected no seccomp profile on k8s, got %q", cfg.Seccomp)
+	}
+}
+
+func TestSandboxMode_Namespace(t *testing.T) {
+	standard := &Config{Command: "echo test", Sandbox: true, Engine: "namespace"}
+	if err := prepare(standard); err != nil {
+		t.Fatalf("expected the standard profile to work with the namespace engine, got %v", err)
+	}
+	if standard.User != "" || standard.PidsLimit != 0 || standard.Memory != 0 || standard.CPU != 0 || !standard.DisableNetwork {
+		t.Errorf("expected only the profile settings the namespace engine enforces, got %+v", standard)
+	}
+
+	err := prepare(&Config{Command: "echo test", Sandbox: true, Engine: "namespace", Memory: 512})
+	if err == nil || !strings.Contains(err.Error(), "memory not supported by the namespace engine") {
+		t.Errorf("expected an explicit memory limit to be rejected, got %v", err)
+	}
+
+	if err := RegisterSandboxProfile("namespace-command-test", &SandboxProfile{
+		Tmpfs:   map[string]string{"/var/tmp": "rw,size=1m"},
+		Network: sandbox.NetworkNone,
+		Seccomp: "strict",
+	}); err != nil {
+		t.Fatalf("RegisterSandboxProfile: %v", err)
+	}
+
+	cfg := &Config{
+		Command:        "echo test",
+		Sandbox:        true,
+		SandboxProfile: "namespace-command-test",
+		Engine:         "namespace",
+		Hostname:       "sandbox",
+	}
+	if err := prepare(cfg); err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+
+	ncfg := newNamespaceConfig(cfg)
+	if ncfg.Seccomp != "strict" || ncfg.Hostname != "sandbox" || ncfg.Tmpfs["/var/tmp"] != "rw,size=1m" || !ncfg.DisableNetwork {
+		t.Errorf("expected the profile settings passed to the namespace engine, got %+v", ncfg)
+	}
+}
@@ -0,0 +1,20 @@
+package command
+
+import (
+	"github.com/go-zoox/command/engine/dind"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/host"
+	"github.com/go-zoox/command/engine/k8s"
+	"github.com/go-zoox/command/engine/podman"
+	"github.com/go-zoox/command/engine/ssh"
+)
+
+// isScriptSupported reports whether the engine can stage a script file.
+func isScriptSupported(name string) bool {
+	switch name {
+	case host.Name, docker.Name, podman.Name, dind.Name, ssh.Name, k8s.Name:
+		return true
+	default:
+		return false
+	}
+}
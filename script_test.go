@@ -0,0 +1,56 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScript_Host(t *testing.T) {
+	cmd, err := New(&Config{
+		Script: `
+msg='it'"'"'s "quoted"'
+echo "$msg"
+echo "$0" | grep -q go-zoox-command-script- && echo staged
+`,
+		Interpreter: "/bin/sh -eu",
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Output() failed: %v", err)
+	}
+	if v := string(out); !strings.Contains(v, `it's "quoted"`) || !strings.Contains(v, "staged") {
+		t.Errorf("unexpected script output: %q", v)
+	}
+}
+
+func TestScript_ExitCode(t *testing.T) {
+	cmd, err := New(&Config{
+		Script:      "false\necho unreachable\n",
+		Interpreter: "/bin/sh -e",
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	cmd.SetStdout(&strings.Builder{})
+
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected script to fail with -e")
+	}
+	if code := cmd.Result().ExitCode; code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+}
+
+func TestScript_ExclusiveWithCommand(t *testing.T) {
+	_, err := New(&Config{
+		Command: "echo hello",
+		Script:  "echo hello",
+	})
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("expected mutually exclusive error, got %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+package seccomp
+
+import "golang.org/x/sys/unix"
+
+const auditArch = unix.AUDIT_ARCH_X86_64
+
+// x32Bit marks the syscalls of the x32 ABI, which share the architecture.
+const x32Bit = 0x40000000
@@ -0,0 +1,10 @@
+//go:build linux && !amd64 && !arm64
+
+package seccomp
+
+const auditArch = 0
+
+const x32Bit = 0
+
+// syscalls is not available on this architecture.
+var syscalls map[string]uint32
@@ -0,0 +1,328 @@
+package seccomp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unsafe"
+
+	dockerseccomp "github.com/docker/docker/profiles/seccomp"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+)
+
+// offsets in struct seccomp_data
+const (
+	offsetNr   = 0
+	offsetArch = 4
+	offsetArgs = 16
+)
+
+// next is the jump target of the following instruction.
+const next = -1
+
+// Filter compiles the profile into a seccomp-bpf program for the native
+// architecture. Syscalls of other architectures kill the process. Rules
+// depending on capabilities are skipped, as the command does not get any.
+func Filter(p *Profile) ([]unix.SockFilter, error) {
+	if syscalls == nil {
+		return nil, fmt.Errorf("seccomp: architecture is not supported")
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := dockerseccomp.LoadProfile(string(body), &specs.Spec{
+		Process: &specs.Process{Capabilities: &specs.LinuxCapabilities{}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("seccomp: %v", err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("seccomp: profile is empty")
+	}
+
+	defaultAction, err := action(profile.DefaultAction, profile.DefaultErrnoRet)
+	if err != nil {
+		return nil, err
+	}
+
+	// rules by syscall number, in profile order
+	rules := map[uint32][]specs.LinuxSyscall{}
+	for _, call := range profile.Syscalls {
+		for _, name := range call.Names {
+			// syscalls of other architectures
+			nr, ok := syscalls[name]
+			if !ok {
+				continue
+			}
+
+			rules[nr] = append(rules[nr], call)
+		}
+	}
+
+	nrs := make([]uint32, 0, len(rules))
+	for nr := range rules {
+		nrs = append(nrs, nr)
+	}
+	sort.Slice(nrs, func(i, j int) bool {
+		return nrs[i] < nrs[j]
+	})
+
+	a := &assembler{}
+
+	native := a.label()
+	a.load(offsetArch)
+	a.jump(unix.BPF_JEQ, auditArch, native, next)
+	a.ret(unix.SECCOMP_RET_KILL_PROCESS)
+	a.bind(native)
+
+	a.load(offsetNr)
+	if x32Bit != 0 {
+		x64 := a.label()
+		a.jump(unix.BPF_JGE, x32Bit, next, x64)
+		a.ret(unix.SECCOMP_RET_KILL_PROCESS)
+		a.bind(x64)
+	}
+
+	// dispatch: a far jump to the block of each syscall
+	blocks := map[uint32]int{}
+	for _, nr := range nrs {
+		blocks[nr] = a.label()
+		skip := a.label()
+		a.jump(unix.BPF_JEQ, nr, next, skip)
+		a.jumpTo(blocks[nr])
+		a.bind(skip)
+	}
+	a.ret(defaultAction)
+
+	for _, nr := range nrs {
+		a.bind(blocks[nr])
+		for _, rule := range rules[nr] {
+			ret, err := action(rule.Action, rule.ErrnoRet)
+			if err != nil {
+				return nil, err
+			}
+
+			fail := a.label()
+			for _, arg := range rule.Args {
+				if err := a.compare(arg, fail); err != nil {
+					return nil, err
+				}
+			}
+			a.ret(ret)
+			a.bind(fail)
+		}
+		a.ret(defaultAction)
+	}
+
+	return a.assemble()
+}
+
+// action returns the seccomp return value of the action.
+func action(act specs.LinuxSeccompAction, errnoRet *uint) (uint32, error) {
+	errno := uint32(unix.EPERM)
+	if errnoRet != nil {
+		errno = uint32(*errnoRet)
+	}
+
+	switch act {
+	case specs.ActAllow:
+		return unix.SECCOMP_RET_ALLOW, nil
+	case specs.ActErrno:
+		return unix.SECCOMP_RET_ERRNO | errno&unix.SECCOMP_RET_DATA, nil
+	case specs.ActKill, specs.ActKillThread:
+		return unix.SECCOMP_RET_KILL_THREAD, nil
+	case specs.ActKillProcess:
+		return unix.SECCOMP_RET_KILL_PROCESS, nil
+	case specs.ActTrap:
+		return unix.SECCOMP_RET_TRAP, nil
+	case specs.ActTrace:
+		return unix.SECCOMP_RET_TRACE | errno&unix.SECCOMP_RET_DATA, nil
+	case specs.ActLog:
+		return unix.SECCOMP_RET_LOG, nil
+	default:
+		return 0, fmt.Errorf("seccomp: action %s is not supported", act)
+	}
+}
+
+// instruction is a bpf instruction with symbolic jump targets.
+type instruction struct {
+	unix.SockFilter
+	jt, jf, ja int
+}
+
+// assembler builds a bpf program with forward jumps to labels.
+type assembler struct {
+	instructions []instruction
+	labels       []int
+}
+
+func (a *assembler) label() int {
+	a.labels = append(a.labels, -1)
+	return len(a.labels) - 1
+}
+
+func (a *assembler) bind(label int) {
+	a.labels[label] = len(a.instructions)
+}
+
+func (a *assembler) emit(code uint16, k uint32, jt, jf, ja int) {
+	a.instructions = append(a.instructions, instruction{
+		SockFilter: unix.SockFilter{Code: code, K: k},
+		jt:         jt,
+		jf:         jf,
+		ja:         ja,
+	})
+}
+
+func (a *assembler) load(offset uint32) {
+	a.emit(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, offset, next, next, next)
+}
+
+func (a *assembler) and(k uint32) {
+	a.emit(unix.BPF_ALU|unix.BPF_AND|unix.BPF_K, k, next, next, next)
+}
+
+func (a *assembler) jump(op uint16, k uint32, jt, jf int) {
+	a.emit(unix.BPF_JMP|op|unix.BPF_K, k, jt, jf, next)
+}
+
+func (a *assembler) jumpTo(label int) {
+	a.emit(unix.BPF_JMP|unix.BPF_JA, 0, next, next, label)
+}
+
+func (a *assembler) ret(k uint32) {
+	a.emit(unix.BPF_RET|unix.BPF_K, k, next, next, next)
+}
+
+// compare jumps to fail unless the 64-bit argument matches, comparing the
+// high and the low 32 bits (little endian).
+func (a *assembler) compare(arg specs.LinuxSeccompArg, fail int) error {
+	if arg.Index > 5 {
+		return fmt.Errorf("seccomp: invalid argument index: %d", arg.Index)
+	}
+
+	lo := offsetArgs + 8*uint32(arg.Index)
+	hi := lo + 4
+	value := func(v uint64) (uint32, uint32) {
+		return uint32(v >> 32), uint32(v)
+	}
+	vhi, vlo := value(arg.Value)
+	pass := a.label()
+
+	switch arg.Op {
+	case specs.OpEqualTo:
+		a.load(hi)
+		a.jump(unix.BPF_JEQ, vhi, next, fail)
+		a.load(lo)
+		a.jump(unix.BPF_JEQ, vlo, next, fail)
+	case specs.OpNotEqual:
+		a.load(hi)
+		a.jump(unix.BPF_JEQ, vhi, next, pass)
+		a.load(lo)
+		a.jump(unix.BPF_JEQ, vlo, fail, next)
+	case specs.OpMaskedEqual:
+		// Value is the mask, ValueTwo the expected value
+		ehi, elo := value(arg.ValueTwo)
+		a.load(hi)
+		a.and(vhi)
+		a.jump(unix.BPF_JEQ, ehi, next, fail)
+		a.load(lo)
+		a.and(vlo)
+		a.jump(unix.BPF_JEQ, elo, next, fail)
+	case specs.OpGreaterThan, specs.OpGreaterEqual:
+		a.load(hi)
+		a.jump(unix.BPF_JGT, vhi, pass, next)
+		a.jump(unix.BPF_JEQ, vhi, next, fail)
+		a.load(lo)
+		op := uint16(unix.BPF_JGT)
+		if arg.Op == specs.OpGreaterEqual {
+			op = unix.BPF_JGE
+		}
+		a.jump(op, vlo, next, fail)
+	case specs.OpLessThan, specs.OpLessEqual:
+		a.load(hi)
+		a.jump(unix.BPF_JGT, vhi, fail, next)
+		a.jump(unix.BPF_JEQ, vhi, next, pass)
+		a.load(lo)
+		op := uint16(unix.BPF_JGE)
+		if arg.Op == specs.OpLessEqual {
+			op = unix.BPF_JGT
+		}
+		a.jump(op, vlo, fail, next)
+	default:
+		return fmt.Errorf("seccomp: operator %s is not supported", arg.Op)
+	}
+
+	a.bind(pass)
+	return nil
+}
+
+// assemble resolves the labels into relative jumps.
+func (a *assembler) assemble() ([]unix.SockFilter, error) {
+	if len(a.instructions) > unix.BPF_MAXINSNS {
+		return nil, fmt.Errorf("seccomp: filter is too large: %d instructions", len(a.instructions))
+	}
+
+	offset := func(i, label int) (uint32, error) {
+		if label == next {
+			return 0, nil
+		}
+
+		target := a.labels[label]
+		if target <= i {
+			return 0, fmt.Errorf("seccomp: invalid jump")
+		}
+
+		return uint32(target - i - 1), nil
+	}
+
+	filter := make([]unix.SockFilter, len(a.instructions))
+	for i, ins := range a.instructions {
+		filter[i] = ins.SockFilter
+
+		if ins.Code&0x07 == unix.BPF_JMP {
+			if ins.ja != next {
+				k, err := offset(i, ins.ja)
+				if err != nil {
+					return nil, err
+				}
+				filter[i].K = k
+				continue
+			}
+
+			jt, err := offset(i, ins.jt)
+			if err != nil {
+				return nil, err
+			}
+			jf, err := offset(i, ins.jf)
+			if err != nil {
+				return nil, err
+			}
+			if jt > 255 || jf > 255 {
+				return nil, fmt.Errorf("seccomp: jump is too far")
+			}
+			filter[i].Jt, filter[i].Jf = uint8(jt), uint8(jf)
+		}
+	}
+
+	return filter, nil
+}
+
+// Install installs the filter on the calling thread, which is inherited by
+// the programs it executes. no_new_privs has to be set before.
+func Install(filter []unix.SockFilter) error {
+	prog := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER, 0, uintptr(unsafe.Pointer(&prog))); errno != 0 {
+		return fmt.Errorf("failed to install seccomp filter: %v", errno)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package seccomp
+
+import (
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+)
+
+func TestFilter_Presets(t *testing.T) {
+	if syscalls == nil {
+		t.Skip("architecture is not supported")
+	}
+
+	for _, name := range []string{Default, Strict, NoNetwork} {
+		p, err := Load(name)
+		if err != nil {
+			t.Fatalf("Load %s: %v", name, err)
+		}
+
+		filter, err := Filter(p)
+		if err != nil {
+			t.Fatalf("Filter %s: %v", name, err)
+		}
+		if len(filter) == 0 || len(filter) > unix.BPF_MAXINSNS {
+			t.Errorf("unexpected filter size of %s: %d", name, len(filter))
+		}
+	}
+}
+
+func TestFilter_UnsupportedAction(t *testing.T) {
+	if syscalls == nil {
+		t.Skip("architecture is not supported")
+	}
+
+	p, err := Load(`{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [{"names": ["mkdir"], "action": "SCMP_ACT_NOTIFY"}]}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if _, err := Filter(p); err == nil {
+		t.Errorf("expected error for action %s", specs.ActNotify)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build ignore
+
+// gen_syscalls generates the syscall tables of the supported architectures
+// from the syscall numbers of golang.org/x/sys/unix.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var syscallRe = regexp.MustCompile(`^\s+SYS_([A-Z0-9_]+)\s+= (\d+)`)
+
+func main() {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "golang.org/x/sys").Output()
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir := strings.TrimSpace(string(out))
+
+	for _, arch := range os.Args[1:] {
+		f, err := os.Open(filepath.Join(dir, "unix", fmt.Sprintf("zsysnum_linux_%s.go", arch)))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		b := &bytes.Buffer{}
+		fmt.Fprintf(b, "// Code generated by gen_syscalls.go; DO NOT EDIT.\n\npackage seccomp\n\n")
+		fmt.Fprintf(b, "// syscalls maps the syscall names to their numbers.\nvar syscalls = map[string]uint32{\n")
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if m := syscallRe.FindStringSubmatch(scanner.Text()); m != nil {
+				fmt.Fprintf(b, "%q: %s,\n", strings.ToLower(m[1]), m[2])
+			}
+		}
+		f.Close()
+		fmt.Fprintf(b, "}\n")
+
+		src, err := format.Source(b.Bytes())
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := os.WriteFile(fmt.Sprintf("syscalls_linux_%s.go", arch), src, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
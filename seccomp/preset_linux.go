@@ -0,0 +1,66 @@
+package seccomp
+
+import (
+	dockerseccomp "github.com/docker/docker/profiles/seccomp"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+)
+
+// strictDenied are the syscalls the strict preset removes from the default profile.
+var strictDenied = []string{
+	"ptrace", "process_vm_readv", "process_vm_writev", "kcmp", "pidfd_getfd",
+	"unshare", "setns",
+	"keyctl", "add_key", "request_key",
+	"io_uring_setup", "io_uring_enter", "io_uring_register",
+	"userfaultfd",
+	"personality",
+}
+
+// preset derives the preset from the default profile of Docker.
+func preset(name string) (*Profile, error) {
+	p := dockerseccomp.DefaultProfile()
+
+	switch name {
+	case Strict:
+		deny(p, strictDenied...)
+	case NoNetwork:
+		deny(p, "socket")
+		p.Syscalls = append(p.Syscalls, &Syscall{
+			LinuxSyscall: specs.LinuxSyscall{
+				Names:  []string{"socket"},
+				Action: specs.ActAllow,
+				Args: []specs.LinuxSeccompArg{
+					{Index: 0, Value: unix.AF_UNIX, Op: specs.OpEqualTo},
+				},
+			},
+			Comment: "only unix sockets",
+		})
+	}
+
+	return p, nil
+}
+
+// deny removes the syscalls from all rules, so the default action (errno) applies.
+func deny(p *Profile, names ...string) {
+	denied := map[string]bool{}
+	for _, name := range names {
+		denied[name] = true
+	}
+
+	syscalls := p.Syscalls[:0]
+	for _, call := range p.Syscalls {
+		kept := []string{}
+		for _, name := range call.Names {
+			if !denied[name] {
+				kept = append(kept, name)
+			}
+		}
+		if len(kept) == 0 {
+			continue
+		}
+
+		call.Names = kept
+		syscalls = append(syscalls, call)
+	}
+	p.Syscalls = syscalls
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package seccomp
+
+import "fmt"
+
+// preset is only available on linux, where the default profile of Docker is defined.
+func preset(name string) (*Profile, error) {
+	return nil, fmt.Errorf("seccomp: preset %s is only supported on linux", name)
+}
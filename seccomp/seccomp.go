@@ -0,0 +1,72 @@
+// Package seccomp loads Docker-format seccomp profiles and built-in presets,
+// and compiles them into seccomp-bpf filters for the host engine.
+package seccomp
+
+//go:generate go run gen_syscalls.go amd64 arm64
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	dockerseccomp "github.com/docker/docker/profiles/seccomp"
+)
+
+// Profile is a Docker-format seccomp profile.
+type Profile = dockerseccomp.Seccomp
+
+// Syscall is a rule of a profile.
+type Syscall = dockerseccomp.Syscall
+
+const (
+	// Default is the default profile of Docker.
+	Default = "default"
+	// Strict is the default profile without ptrace, namespaces, keyrings, io_uring and userfaultfd.
+	Strict = "strict"
+	// NoNetwork is the default profile which only allows unix sockets.
+	NoNetwork = "no-network"
+)
+
+// Load returns the profile of a preset name, an inline JSON profile or the path of a JSON profile.
+func Load(profile string) (*Profile, error) {
+	switch profile {
+	case Default, Strict, NoNetwork:
+		return preset(profile)
+	}
+
+	body := []byte(profile)
+	if !strings.HasPrefix(strings.TrimSpace(profile), "{") {
+		var err error
+		if body, err = os.ReadFile(profile); err != nil {
+			return nil, fmt.Errorf("seccomp: failed to read profile: %v", err)
+		}
+	}
+
+	p := &Profile{}
+	if err := json.Unmarshal(body, p); err != nil {
+		return nil, fmt.Errorf("seccomp: failed to decode profile: %v", err)
+	}
+
+	return p, nil
+}
+
+// SecurityOpt returns the container security option applying the profile.
+// It is empty for the default preset, which the container runtime applies itself.
+func SecurityOpt(profile string) (string, error) {
+	if profile == "" || profile == Default {
+		return "", nil
+	}
+
+	p, err := Load(profile)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	return "seccomp=" + string(body), nil
+}
@@ -0,0 +1,88 @@
+package seccomp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// rules returns the rules of the syscall.
+func rules(p *Profile, name string) []*Syscall {
+	matched := []*Syscall{}
+	for _, call := range p.Syscalls {
+		for _, n := range call.Names {
+			if n == name {
+				matched = append(matched, call)
+			}
+		}
+	}
+	return matched
+}
+
+func TestLoad_Presets(t *testing.T) {
+	p, err := Load(Default)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(rules(p, "ptrace")) == 0 || len(rules(p, "socket")) == 0 {
+		t.Error("expected ptrace and socket allowed by the default preset")
+	}
+
+	p, err = Load(Strict)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(rules(p, "ptrace")) != 0 || len(rules(p, "socket")) == 0 {
+		t.Error("expected ptrace denied and socket allowed by the strict preset")
+	}
+
+	p, err = Load(NoNetwork)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	socket := rules(p, "socket")
+	if len(socket) != 1 || len(socket[0].Args) != 1 || socket[0].Args[0].Value != unix.AF_UNIX || len(rules(p, "ptrace")) == 0 {
+		t.Error("expected only unix sockets and ptrace allowed by the no-network preset")
+	}
+}
+
+func TestLoad_Profile(t *testing.T) {
+	profile := `{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [{"names": ["mkdir", "mkdirat"], "action": "SCMP_ACT_ERRNO"}]}`
+
+	p, err := Load(profile)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if p.DefaultAction != "SCMP_ACT_ALLOW" || len(p.Syscalls) != 1 {
+		t.Errorf("unexpected profile: %+v", p)
+	}
+
+	path := filepath.Join(t.TempDir(), "profile.json")
+	if err := os.WriteFile(path, []byte(profile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if p, err = Load(path); err != nil || len(p.Syscalls) != 1 {
+		t.Errorf("expected profile loaded from file, got %+v (%v)", p, err)
+	}
+
+	if _, err := Load("unknown"); err == nil {
+		t.Error("expected error for a missing profile file")
+	}
+}
+
+func TestSecurityOpt(t *testing.T) {
+	if opt, err := SecurityOpt(Default); err != nil || opt != "" {
+		t.Errorf("expected the runtime default for the default preset, got %q (%v)", opt, err)
+	}
+
+	opt, err := SecurityOpt(Strict)
+	if err != nil {
+		t.Fatalf("SecurityOpt: %v", err)
+	}
+	if !strings.HasPrefix(opt, "seccomp={") || strings.Contains(opt, `"ptrace"`) {
+		t.Errorf("expected the strict profile as json, got %.80s...", opt)
+	}
+}
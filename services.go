@@ -0,0 +1,45 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/go-zoox/command/config"
+	"github.com/go-zoox/command/engine/docker"
+	"github.com/go-zoox/command/engine/podman"
+)
+
+// Service is an auxiliary container started next to the command, e.g. a database.
+type Service = config.Service
+
+// serviceNameRe matches the service names, which are host names on the private network of the run.
+var serviceNameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// checkServices validates the services for the engine.
+func checkServices(cfg *Config) error {
+	switch cfg.Engine {
+	case docker.Name, podman.Name:
+	default:
+		return fmt.Errorf("services are only supported by docker and podman engines, but got: %s", cfg.Engine)
+	}
+
+	names := map[string]bool{}
+	for _, service := range cfg.Services {
+		if !serviceNameRe.MatchString(service.Name) {
+			return fmt.Errorf("invalid service name: %q, must be a lowercase host name", service.Name)
+		}
+		if names[service.Name] {
+			return fmt.Errorf("duplicate service name: %s", service.Name)
+		}
+		names[service.Name] = true
+
+		if service.Image == "" {
+			return fmt.Errorf("service %s requires an image", service.Name)
+		}
+		if service.HealthTimeout < 0 {
+			return fmt.Errorf("service %s has a negative health timeout", service.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckServices(t *testing.T) {
+	cfg := &Config{Engine: "docker", Services: []Service{
+		{Name: "db", Image: "postgres:16-alpine", HealthCheck: "pg_isready"},
+		{Name: "redis-cache", Image: "redis:7-alpine"},
+	}}
+	if err := checkServices(cfg); err != nil {
+		t.Errorf("checkServices: %v", err)
+	}
+
+	for _, tc := range []struct {
+		engine   string
+		services []Service
+		err      string
+	}{
+		{"host", []Service{{Name: "db", Image: "postgres"}}, "only supported by docker and podman"},
+		{"docker", []Service{{Name: "DB", Image: "postgres"}}, "invalid service name"},
+		{"docker", []Service{{Name: "db_1", Image: "postgres"}}, "invalid service name"},
+		{"docker", []Service{{Name: "db", Image: "postgres"}, {Name: "db", Image: "mysql"}}, "duplicate service name"},
+		{"podman", []Service{{Name: "db"}}, "requires an image"},
+		{"podman", []Service{{Name: "db", Image: "postgres", HealthTimeout: -time.Second}}, "negative health timeout"},
+	} {
+		err := checkServices(&Config{Engine: tc.engine, Services: tc.services})
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%+v: expected error containing %q, got %v", tc.services, tc.err, err)
+		}
+	}
+}
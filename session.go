@@ -0,0 +1,79 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-zoox/command/engine"
+)
+
+// Session is a long-lived execution environment which runs many commands.
+// Commands executed in the same session share installed packages, the working
+// directory and exported environment variables.
+type Session interface {
+	Exec(command string) (Command, error)
+	Close() error
+}
+
+// NewSession creates a new session.
+func NewSession(cfg *Config) (Session, error) {
+	if err := prepare(cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Agent != "" {
+		return nil, errors.New("session is not supported by agent")
+	}
+
+	createSession, err := engine.GetSession(cfg.Engine)
+	if err != nil {
+		return nil, fmt.Errorf("session is not supported by engine: %s", cfg.Engine)
+	}
+
+	es, err := createSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-cfg.Context.Done()
+		es.Close()
+	}()
+
+	return &session{
+		cfg:     cfg,
+		session: es,
+	}, nil
+}
+
+type session struct {
+	cfg *Config
+	//
+	session engine.Session
+}
+
+// Exec creates a command running in the session.
+func (s *session) Exec(command string) (Command, error) {
+	cfg := *s.cfg
+	resetChildConfig(&cfg)
+	cfg.Command = command
+
+	eg, err := s.session.Exec(&engine.ExecConfig{
+		Command:     cfg.Command,
+		Environment: cfg.Environment,
+		WorkDir:     cfg.WorkDir,
+		User:        cfg.User,
+		Shell:       cfg.Shell,
+		ReadOnly:    cfg.ReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newCommand(&cfg, eg), nil
+}
+
+// Close tears the session environment down.
+func (s *session) Close() error {
+	return s.session.Close()
+}
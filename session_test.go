@@ -0,0 +1,64 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSession_SharesState(t *testing.T) {
+	s, err := NewSession(&Config{
+		WorkDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	defer s.Close()
+
+	setup, err := s.Exec("export GREETING=hello; mkdir -p sub; cd sub")
+	if err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	if err := setup.Run(); err != nil {
+		t.Fatalf("failed to run setup: %v", err)
+	}
+
+	check, err := s.Exec(`echo "$GREETING $(basename "$(pwd)")"`)
+	if err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	out, err := check.Output()
+	if err != nil {
+		t.Fatalf("failed to run check: %v", err)
+	}
+	if v := strings.TrimSpace(string(out)); v != "hello sub" {
+		t.Errorf("expected %q, got %q", "hello sub", v)
+	}
+}
+
+func TestSession_ExitCode(t *testing.T) {
+	s, err := NewSession(&Config{})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	defer s.Close()
+
+	cmd, err := s.Exec("exit 3")
+	if err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected error for exit 3")
+	}
+}
+
+func TestSession_UnsupportedEngine(t *testing.T) {
+	_, err := NewSession(&Config{
+		Engine: "caas",
+	})
+	if err == nil {
+		t.Fatal("expected error for engine without session support")
+	}
+	if !strings.Contains(err.Error(), "session is not supported") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
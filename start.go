@@ -1,6 +1,9 @@
 package command
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // Start starts to run the command.
 func (c *command) Start() error {
@@ -8,9 +11,20 @@ func (c *command) Start() error {
 		return errors.New("engine not set")
 	}
 
-	if c.cfg.Command == "" {
+	if c.cfg.Command == "" && c.cfg.Script == "" {
 		return errors.New("command is required")
 	}
 
-	return c.engine.Start()
+	if c.cfg.ReportChanges {
+		if err := c.snapshotChanges(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.engine.Start(); err != nil {
+		return err
+	}
+
+	c.startedAt = time.Now()
+	return nil
 }
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-zoox/command/engine"
+	"github.com/go-zoox/command/result"
+)
+
+// Stats is the resource usage of a command.
+type Stats = result.Stats
+
+// Stats returns the resource usage of the command, sampled live while it runs
+// and the final summary once it has exited.
+func (c *command) Stats() (*Stats, error) {
+	if c.result != nil && c.result.Stats != nil {
+		stats := *c.result.Stats
+		return &stats, nil
+	}
+
+	if c.startedAt.IsZero() {
+		return nil, errors.New("command is not started")
+	}
+
+	reader, ok := c.engine.(engine.StatsReader)
+	if !ok {
+		return nil, fmt.Errorf("stats are not supported by engine: %s", c.cfg.Engine)
+	}
+
+	stats, err := reader.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	stats.WallTime = time.Since(c.startedAt)
+	return stats, nil
+}
+
+// summarizeStats returns the final resource usage once the command has exited.
+// Engines without stats report the wall time only.
+func (c *command) summarizeStats() *Stats {
+	stats := &Stats{}
+	if reader, ok := c.engine.(engine.StatsReader); ok {
+		if s, err := reader.Stats(); err == nil {
+			stats = s
+		}
+	}
+
+	if !c.startedAt.IsZero() {
+		stats.WallTime = time.Since(c.startedAt)
+	}
+
+	return stats
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStats_Host(t *testing.T) {
+	cmd, err := New(&Config{
+		Command: "i=0; while [ $i -lt 100000 ]; do i=$((i+1)); done; sleep 0.3",
+	})
+	if err != nil {
+		t.Fatalf("failed to create command: %v", err)
+	}
+	cmd.SetStdout(&strings.Builder{})
+
+	if _, err := cmd.Stats(); err == nil {
+		t.Error("expected Stats() to fail before Start")
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+
+	if runtime.GOOS == "linux" {
+		// let the shell finish exec'ing
+		time.Sleep(50 * time.Millisecond)
+
+		live, err := cmd.Stats()
+		if err != nil {
+			t.Fatalf("live Stats() failed: %v", err)
+		}
+		if live.Memory <= 0 {
+			t.Errorf("expected live memory usage, got %+v", live)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("Wait() failed: %v", err)
+	}
+
+	stats := cmd.Result().Stats
+	if stats == nil {
+		t.Fatal("expected stats on the result")
+	}
+	if stats.CPUTime <= 0 || stats.CPUTime != stats.UserTime+stats.SystemTime {
+		t.Errorf("unexpected cpu time: %+v", stats)
+	}
+	if stats.PeakMemory <= 0 {
+		t.Errorf("expected peak memory, got %+v", stats)
+	}
+	if stats.WallTime < 300*time.Millisecond {
+		t.Errorf("expected wall time of at least 300ms, got %s", stats.WallTime)
+	}
+
+	final, err := cmd.Stats()
+	if err != nil || *final != *stats {
+		t.Errorf("expected Stats() after exit to return the summary, got %+v (%v)", final, err)
+	}
+}
@@ -3,10 +3,16 @@ package command
 import (
 	"fmt"
 	"time"
+
+	"github.com/go-zoox/command/errors"
 )
 
 // Wait waits for the command to exit.
 func (c *command) Wait() error {
+	return c.finish(c.wait())
+}
+
+func (c *command) wait() error {
 	if c.cfg.Timeout != 0 {
 		done := make(chan error)
 		go func() {
@@ -18,7 +24,11 @@ func (c *command) Wait() error {
 			return c.cfg.Context.Err()
 		case <-time.After(c.cfg.Timeout):
 			c.Cancel()
-			return fmt.Errorf("timeout to run command (command: %s, timeout: %s)", c.cfg.Command, c.cfg.Timeout)
+			return &errors.ExitError{
+				Code:    -1,
+				Message: fmt.Sprintf("timeout to run command (command: %s, timeout: %s)", c.cfg.Command, c.cfg.Timeout),
+				Reason:  errors.ReasonTimeout,
+			}
 		case err := <-done:
 			return err
 		}